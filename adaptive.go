@@ -0,0 +1,78 @@
+package rc
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// notifyChannel returns a channel for notifications
+// about added triggers
+func (c *Client) notifyChannel() string {
+	return fmt.Sprintf("%s:notify", c.prefix)
+}
+
+// notify provides publishing of the scheduled time
+// of the added trigger in the adaptive mode
+func (c *Client) notify(t *Trigger) {
+	if !c.adaptive {
+		return
+	}
+
+	err := c.c.Publish(c.notifyChannel(), t.scheduledTime().UnixNano()).Err()
+	if err != nil {
+		log.Printf("unable to notify about added trigger: %v", err)
+	}
+}
+
+func (c *Client) subscribeNotify() (*redis.PubSub, error) {
+	ps := c.c.Subscribe(c.notifyChannel())
+	if _, err := ps.Receive(); err != nil {
+		ps.Close()
+		return nil, err
+	}
+	return ps, nil
+}
+
+// waitNotify provides waiting until the next trigger is ready
+// or until the notification about the earlier trigger. Overdue
+// triggers which were not fired are checked after the interval.
+// Notifications are not persisted by Redis, so they are lost while
+// the subscription is reconnected, and triggers which are added by
+// clients without Adaptive option are not notified. Thus waiting
+// is limited by SafetyPollInterval
+func (c *Client) waitNotify(wake <-chan *redis.Message) {
+
+	now := c.now()
+	next, ok, err := c.NextFireTime()
+	if err != nil {
+		log.Printf("unable to get next fire time: %v", err)
+		ok, next = true, now.Add(c.interval)
+	}
+	if ok && !next.After(now) {
+		next = now.Add(c.interval)
+	}
+	if safety := now.Add(c.safetyPollInterval); !ok || next.After(safety) {
+		ok, next = true, safety
+	}
+
+	timer := time.NewTimer(next.Sub(now))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-timer.C:
+			return
+		case msg := <-wake:
+			ns, pErr := strconv.ParseInt(msg.Payload, base10, 64)
+			if pErr != nil || !ok || time.Unix(0, ns).Before(next) {
+				return
+			}
+		}
+	}
+}
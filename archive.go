@@ -0,0 +1,90 @@
+package rc
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// Replay provides scheduling of triggers which were fired
+// from the time from to the time to for immediate firing.
+// Only archived triggers are replayed, see Archive option.
+// Replayed triggers keep their IDs and are not archived again.
+// Recurring triggers are replayed as one-shot copies of the fired
+// occurrence with new IDs, so their series are not duplicated
+// and keep their IDs.
+// It returns number of scheduled triggers
+func (c *Client) Replay(from, to time.Time) (int, error) {
+
+	members, err := c.c.ZRangeByScore(c.archiveKey(), redis.ZRangeBy{
+		Min: strconv.FormatInt(toMillis(from), base10),
+		Max: strconv.FormatInt(toMillis(to), base10),
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("unable to get archived triggers: %v", err)
+	}
+
+	var replayed int
+	now := c.now()
+	for _, m := range members {
+		var t *Trigger
+		t, err = c.decode(m)
+		if err != nil {
+			continue
+		}
+		t.DateTime = now
+		t.EarliestStart = time.Time{}
+		t.Deadline = time.Time{}
+		t.MaxStaleness = 0
+		if t.CronSpec != "" || t.Next != "" {
+			t.ID = ""
+			t.CronSpec = ""
+			t.Next = ""
+		}
+		t.Attempt = 0
+		t.NextAttempt = time.Time{}
+		t.Replay = true
+		if err = c.AddTrigger(t); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
+
+func (c *Client) archiveKey() string {
+	return fmt.Sprintf("%s:archive", c.prefix)
+}
+
+// archiveFired provides storing of the fired trigger
+// in the archive if Archive option is enabled
+func (c *Client) archiveFired(t *Trigger) {
+	if !c.archive || t.Replay {
+		return
+	}
+
+	now := c.now()
+	_, err := c.c.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(c.archiveKey(), redis.Z{
+			Score:  float64(toMillis(now)),
+			Member: t.raw,
+		})
+		if c.archiveRetention > 0 {
+			max := strconv.FormatInt(toMillis(now.Add(-c.archiveRetention)), base10)
+			pipe.ZRemRangeByScore(c.archiveKey(), "-inf", "("+max)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("unable to archive fired trigger: %v", err)
+	}
+}
+
+// toMillis returns unix time of t in milliseconds
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
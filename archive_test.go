@@ -0,0 +1,56 @@
+package rc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReplayRecurringTriggerOnce(t *testing.T) {
+	c, _ := newTestClient(t, func(o *ClientOptions) {
+		o.Archive = true
+	})
+	fired := 0
+	c.RegisterHandler("h", func() { fired++ })
+	c.RegisterNextFunc("hour", func(prev time.Time) time.Time { return prev.Add(time.Hour) })
+
+	from := time.Now().Add(-time.Minute)
+	if err := c.AddTrigger(&Trigger{ID: "a", Handler: "h", DateTime: time.Now().Add(-time.Second), Next: "hour"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ProcessOnce(); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := c.Replay(from, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 replayed trigger, got %d", n)
+	}
+	if _, err = c.ProcessOnce(); err != nil {
+		t.Fatal(err)
+	}
+	if fired != 2 {
+		t.Fatalf("expected replayed trigger to be fired, got %d firings", fired)
+	}
+
+	keys, err := c.getKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pending int
+	for _, k := range keys {
+		ts, gErr := c.getTriggers(k)
+		if gErr != nil {
+			t.Fatal(gErr)
+		}
+		pending += len(ts)
+	}
+	if pending != 1 {
+		t.Fatalf("expected only the next occurrence of the series, got %d pending triggers", pending)
+	}
+	if _, err = c.Get("a"); err != nil {
+		t.Fatalf("expected series to keep its ID: %v", err)
+	}
+}
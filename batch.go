@@ -0,0 +1,56 @@
+package rc
+
+import (
+	"log"
+	"sync"
+
+	"github.com/go-redis/redis"
+)
+
+// firedBatch defines triggers of the one key which were
+// fired and should be removed from it
+type firedBatch struct {
+	mu      sync.Mutex
+	members []interface{}
+	ids     []string
+	errs    []error
+}
+
+func (b *firedBatch) add(t *Trigger, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.members = append(b.members, t.raw)
+	b.ids = append(b.ids, t.ID)
+	b.errs = append(b.errs, err)
+}
+
+// removeFired provides removing of fired triggers of the batch
+// with the one SREM. Key is not deleted entirely, since new triggers
+// can be added to it while triggers are fired, Redis removes
+// it when the last member is removed. Results are sent after
+// triggers are removed, as well as dependents of
+// triggers which handlers are succeeded are released
+func (c *Client) removeFired(key string, b *firedBatch) {
+	if len(b.members) == 0 {
+		return
+	}
+
+	_, err := c.c.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.SRem(key, b.members...)
+		pipe.HDel(c.indexKey, b.ids...)
+		if c.bucketSize > 0 {
+			pipe.HDel(c.overflowIndexKey(), key)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("unable to remove fired triggers: %v", err)
+	}
+
+	for i, m := range b.members {
+		if err == nil && b.errs[i] == nil {
+			c.releaseDependents(b.ids[i])
+		}
+		c.sendResult(m.(string), b.errs[i])
+	}
+}
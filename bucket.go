@@ -0,0 +1,104 @@
+package rc
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// overflowSeparator separates the key and
+// the number of its overflow key
+const overflowSeparator = ":overflow:"
+
+// overflowIndexKey returns key of the hash with the last
+// used overflow key by the key, so inserting doesn't check
+// all full overflow keys from the first one
+func (c *Client) overflowIndexKey() string {
+	return fmt.Sprintf("%s:overflow", c.prefix)
+}
+
+// overflowKey returns the n-th overflow key of the key.
+// Its the key itself for 0
+func overflowKey(key string, n int64) string {
+	if n == 0 {
+		return key
+	}
+	return fmt.Sprintf("%s%s%d", key, overflowSeparator, n)
+}
+
+// storeKey returns key in which trigger is stored. With BucketSize
+// its the first not full key from the key made by KeyFunc
+// and its overflow keys
+func (c *Client) storeKey(t *Trigger) (string, error) {
+
+	if !t.ExpireAt.IsZero() {
+		return c.expiringKey(t), nil
+	}
+
+	key := c.keyFunc(t)
+	if c.bucketSize <= 0 {
+		return key, nil
+	}
+
+	n, err := c.c.HGet(c.overflowIndexKey(), key).Int64()
+	if err != nil && err != redis.Nil {
+		return "", fmt.Errorf("unable to get overflow key: %v", err)
+	}
+	last := n
+	for {
+		k := overflowKey(key, n)
+		var size int64
+		size, err = c.c.SCard(k).Result()
+		if err != nil {
+			return "", fmt.Errorf("unable to get size of the key: %v", err)
+		}
+		if size < c.bucketSize {
+			break
+		}
+		n++
+	}
+
+	if n != last {
+		if err = c.c.HSet(c.overflowIndexKey(), key, n).Err(); err != nil {
+			return "", fmt.Errorf("unable to set overflow key: %v", err)
+		}
+	}
+
+	return overflowKey(key, n), nil
+}
+
+// ParseKeyTime returns the scheduled time of the key
+// in which triggers are stored, including overflow and expiring keys.
+// Key is parsed by ParseKey option or by the default scheme
+func (c *Client) ParseKeyTime(key string) (time.Time, error) {
+	return c.parseKey(baseKey(key))
+}
+
+// keyTime returns the scheduled time of the key and true or false
+// if key can't be parsed, e.g. its a foreign key which matches
+// KeyMatch. Such keys are skipped and logged once
+func (c *Client) keyTime(key string) (time.Time, bool) {
+	t, err := c.ParseKeyTime(key)
+	if err == nil {
+		return t, true
+	}
+	if _, logged := c.foreignKeys.LoadOrStore(key, struct{}{}); !logged {
+		log.Printf("key %s is skipped: %v", key, err)
+	}
+	return time.Time{}, false
+}
+
+// triggerKeys returns keys which time can be parsed by keyTime,
+// so foreign keys which match KeyMatch are skipped
+// by all listing and counting of triggers
+func (c *Client) triggerKeys(keys []string) []string {
+	r := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if _, ok := c.keyTime(k); ok {
+			r = append(r, k)
+		}
+	}
+	return r
+}
@@ -0,0 +1,83 @@
+package rc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForeignKeysAreSkipped(t *testing.T) {
+	c, m := newTestClient(t)
+	fired := 0
+	c.RegisterHandler("h", func() { fired++ })
+	if err := m.Set("rc-config", "value"); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if err := c.AddTrigger(&Trigger{ID: "a", Handler: "h", DateTime: now.Add(-time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddTrigger(&Trigger{ID: "b", Handler: "h", DateTime: now.Add(30 * time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := c.ProcessOnce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Fired != 1 || fired != 1 {
+		t.Fatalf("expected trigger to be fired, got %d", fired)
+	}
+
+	next, ok, err := c.NextFireTime()
+	if err != nil || !ok {
+		t.Fatalf("expected next fire time, got %v %v", ok, err)
+	}
+	if next.Unix() != now.Add(30*time.Second).Unix() {
+		t.Fatalf("expected next fire time of the trigger, got %v", next)
+	}
+	ts, err := c.ListDueWithin(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ts) != 1 || ts[0].ID != "b" {
+		t.Fatalf("expected due trigger, got %v", ts)
+	}
+
+	if ts, _, err = c.List("", 10); err != nil || len(ts) != 1 {
+		t.Fatalf("expected listed trigger, got %v %v", ts, err)
+	}
+	if ts, err = c.ListByLabel("k", "v"); err != nil || len(ts) != 0 {
+		t.Fatalf("expected no labeled triggers, got %v %v", ts, err)
+	}
+	var b bytes.Buffer
+	if err = c.Export(&b); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(b.String(), "\n"); n != 1 {
+		t.Fatalf("expected 1 exported trigger, got %d", n)
+	}
+}
+
+func TestForeignKeysOfNamespaceAreSkipped(t *testing.T) {
+	c, m := newTestClient(t, func(o *ClientOptions) {
+		o.NamespaceKeys = true
+	})
+	if err := m.Set("rc-billing-config", "value"); err != nil {
+		t.Fatal(err)
+	}
+	tr := &Trigger{ID: "a", Handler: "h", Namespace: "billing", DateTime: time.Now().Add(time.Minute)}
+	if err := c.AddTrigger(tr); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := c.CountByNamespace("billing")
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 trigger of the namespace, got %d %v", n, err)
+	}
+	ts, err := c.ListByNamespace("billing")
+	if err != nil || len(ts) != 1 {
+		t.Fatalf("expected listed trigger of the namespace, got %v %v", ts, err)
+	}
+}
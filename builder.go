@@ -0,0 +1,166 @@
+package rc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TriggerBuilder provides building of the trigger by chained
+// calls, see NewTrigger. Its the same as adding of Trigger by Schedule
+type TriggerBuilder struct {
+	c   *Client
+	t   Trigger
+	err error
+}
+
+// NewTrigger returns builder of the trigger which is added by its Schedule:
+//
+//	c.NewTrigger().Handler("sendEmail").At(t).WithPayload(p).Namespace("billing").Schedule()
+func (c *Client) NewTrigger() *TriggerBuilder {
+	return &TriggerBuilder{c: c}
+}
+
+// ID sets ID of the trigger. By default its generated
+func (b *TriggerBuilder) ID(id string) *TriggerBuilder {
+	b.t.ID = id
+	return b
+}
+
+// Handler sets name of the registered handler of the trigger
+func (b *TriggerBuilder) Handler(name string) *TriggerBuilder {
+	b.t.Handler = name
+	return b
+}
+
+// At sets time of the trigger
+func (b *TriggerBuilder) At(t time.Time) *TriggerBuilder {
+	b.t.DateTime = t
+	return b
+}
+
+// In sets time of the trigger after d from now
+func (b *TriggerBuilder) In(d time.Duration) *TriggerBuilder {
+	b.t.DateTime = b.c.now().Add(d)
+	return b
+}
+
+// Cron sets CronSpec of the trigger. Its time is set to the next
+// occurrence from now if its not set by At or In
+func (b *TriggerBuilder) Cron(spec string) *TriggerBuilder {
+	b.t.CronSpec = spec
+	return b
+}
+
+// CronLocation sets CronLocation of the trigger
+func (b *TriggerBuilder) CronLocation(name string) *TriggerBuilder {
+	b.t.CronLocation = name
+	return b
+}
+
+// Namespace sets namespace of the trigger
+func (b *TriggerBuilder) Namespace(namespace string) *TriggerBuilder {
+	b.t.Namespace = namespace
+	return b
+}
+
+// WithPayload sets payload of the trigger. Payload which isn't
+// json.RawMessage or []byte is marshaled to JSON
+func (b *TriggerBuilder) WithPayload(payload interface{}) *TriggerBuilder {
+	switch p := payload.(type) {
+	case json.RawMessage:
+		b.t.Payload = p
+	case []byte:
+		b.t.Payload = p
+	default:
+		m, err := json.Marshal(p)
+		if err != nil {
+			b.err = fmt.Errorf("unable to marshal payload: %v", err)
+			return b
+		}
+		b.t.Payload = m
+	}
+	return b
+}
+
+// WithLabel sets the label of the trigger
+func (b *TriggerBuilder) WithLabel(key, value string) *TriggerBuilder {
+	if b.t.Labels == nil {
+		b.t.Labels = map[string]string{}
+	}
+	b.t.Labels[key] = value
+	return b
+}
+
+// WithMeta sets the key of Meta of the trigger
+func (b *TriggerBuilder) WithMeta(key, value string) *TriggerBuilder {
+	if b.t.Meta == nil {
+		b.t.Meta = map[string]string{}
+	}
+	b.t.Meta[key] = value
+	return b
+}
+
+// DedupKey sets DedupKey of the trigger
+func (b *TriggerBuilder) DedupKey(key string) *TriggerBuilder {
+	b.t.DedupKey = key
+	return b
+}
+
+// CoalesceKey sets CoalesceKey of the trigger
+func (b *TriggerBuilder) CoalesceKey(key string) *TriggerBuilder {
+	b.t.CoalesceKey = key
+	return b
+}
+
+// MaxAttempts sets MaxAttempts of the trigger
+func (b *TriggerBuilder) MaxAttempts(n int) *TriggerBuilder {
+	b.t.MaxAttempts = n
+	return b
+}
+
+// Deadline sets Deadline of the trigger
+func (b *TriggerBuilder) Deadline(t time.Time) *TriggerBuilder {
+	b.t.Deadline = t
+	return b
+}
+
+// ExpireAt sets ExpireAt of the trigger
+func (b *TriggerBuilder) ExpireAt(t time.Time) *TriggerBuilder {
+	b.t.ExpireAt = t
+	return b
+}
+
+// DependsOn sets IDs of triggers after which the trigger is scheduled
+func (b *TriggerBuilder) DependsOn(ids ...string) *TriggerBuilder {
+	b.t.DependsOn = ids
+	return b
+}
+
+// Trigger returns copy of the built trigger
+func (b *TriggerBuilder) Trigger() (*Trigger, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	t := b.t
+	if t.CronSpec != "" && t.DateTime.IsZero() {
+		next, err := b.c.cronNext(&t)
+		if err != nil {
+			return nil, err
+		}
+		t.DateTime = next(b.c.now())
+		if t.DateTime.IsZero() {
+			return nil, fmt.Errorf("cron spec %q has no next occurrence", t.CronSpec)
+		}
+	}
+	return &t, nil
+}
+
+// Schedule provides adding of the built trigger by Client.Schedule
+func (b *TriggerBuilder) Schedule() (ScheduleResult, error) {
+	t, err := b.Trigger()
+	if err != nil {
+		return ScheduleResult{}, err
+	}
+	return b.c.Schedule(t)
+}
@@ -0,0 +1,161 @@
+package rc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-redis/redis"
+)
+
+// CancelErrors defines errors of cancelling of triggers by ID
+type CancelErrors map[string]error
+
+func (e CancelErrors) Error() string {
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	msgs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", id, e[id]))
+	}
+	return fmt.Sprintf("unable to cancel triggers: %s", strings.Join(msgs, "; "))
+}
+
+// Cancel provides removing of the trigger by the ID. If handler of
+// the trigger is running by the client, its context is cancelled,
+// see RegisterContextHandler. It returns ErrNotFound if trigger
+// is not exist and is not running
+func (c *Client) Cancel(id string) error {
+
+	running := c.cancelRunning(id)
+	removed, err := c.CancelMany([]string{id})
+	if err != nil {
+		if cErr, ok := err.(CancelErrors); ok {
+			return cErr[id]
+		}
+		return err
+	}
+	if removed == 0 && !running {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// CancelMany provides removing of triggers by IDs with pipelines
+// and returns number of removed triggers. Contexts of running handlers
+// of triggers are cancelled as in Cancel. Triggers which depend on
+// removed ones are released as if they were completed. Unknown IDs are skipped.
+// Failures of the particular IDs do not abort removing
+// of another ones and are returned as CancelErrors
+func (c *Client) CancelMany(ids []string) (int, error) {
+
+	if c.isClosed() {
+		return 0, ErrClosed
+	}
+
+	for _, id := range ids {
+		c.cancelRunning(id)
+	}
+	keys, errs := c.resolveKeys(ids)
+
+	members, err := c.resolveMembers(keys)
+	if err != nil {
+		return 0, err
+	}
+
+	pipe := c.c.TxPipeline()
+	sRems := map[string]*redis.IntCmd{}
+	for id, k := range keys {
+		if m, ok := members[id]; ok {
+			sRems[id] = pipe.SRem(k, m)
+		}
+		pipe.HDel(c.indexKey, id)
+	}
+	if len(keys) > 0 {
+		if _, err = pipe.Exec(); err != nil {
+			return 0, c.closedOr(fmt.Errorf("unable to remove triggers: %v", err))
+		}
+	}
+
+	var removed int
+	for id, cmd := range sRems {
+		if cmd.Val() == 0 {
+			continue
+		}
+		removed += int(cmd.Val())
+		if t, dErr := c.decode(members[id]); dErr == nil {
+			c.removed(t)
+		}
+		c.releaseDependents(id)
+	}
+
+	if len(errs) > 0 {
+		return removed, errs
+	}
+	return removed, nil
+}
+
+// resolveKeys returns keys of triggers by IDs from the index
+func (c *Client) resolveKeys(ids []string) (map[string]string, CancelErrors) {
+
+	errs := CancelErrors{}
+	pipe := c.c.Pipeline()
+	hGets := make(map[string]*redis.StringCmd, len(ids))
+	for _, id := range ids {
+		hGets[id] = pipe.HGet(c.indexKey, id)
+	}
+	_, _ = pipe.Exec()
+
+	keys := make(map[string]string, len(ids))
+	for id, cmd := range hGets {
+		key, err := cmd.Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			errs[id] = fmt.Errorf("unable to get key of the trigger: %v", err)
+			continue
+		}
+		keys[id] = key
+	}
+
+	return keys, errs
+}
+
+// resolveMembers returns members of triggers by IDs
+// which are stored in keys
+func (c *Client) resolveMembers(keys map[string]string) (map[string]string, error) {
+
+	pipe := c.c.Pipeline()
+	sMembers := map[string]*redis.StringSliceCmd{}
+	for _, k := range keys {
+		if _, ok := sMembers[k]; !ok {
+			sMembers[k] = pipe.SMembers(k)
+		}
+	}
+	if len(sMembers) > 0 {
+		if _, err := pipe.Exec(); err != nil && err != redis.Nil {
+			return nil, fmt.Errorf("unable to get triggers: %v", err)
+		}
+	}
+
+	members := map[string]string{}
+	for _, cmd := range sMembers {
+		for _, v := range cmd.Val() {
+			t, err := c.decode(v)
+			if err != nil {
+				continue
+			}
+			if _, ok := keys[t.ID]; ok {
+				members[t.ID] = v
+			}
+		}
+	}
+
+	return members, nil
+}
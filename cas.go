@@ -0,0 +1,90 @@
+package rc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// compareAndRescheduleScript provides moving of the trigger
+// to the new key only if it was not changed after it was read
+var compareAndRescheduleScript = redis.NewScript(`
+if redis.call("HGET", KEYS[3], ARGV[3]) ~= KEYS[1] then
+	return 0
+end
+if redis.call("SREM", KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call("SADD", KEYS[2], ARGV[2])
+if ARGV[4] ~= "0" then
+	redis.call("PEXPIREAT", KEYS[2], ARGV[4])
+end
+redis.call("HSET", KEYS[3], ARGV[3], KEYS[2])
+return 1
+`)
+
+// CompareAndReschedule provides atomic moving of the trigger to the
+// newTime only if its DateTime is equal to expectedTime and trigger
+// is not changed concurrently. It returns false if comparing is failed,
+// so caller can read the trigger again and retry. It returns
+// ErrNotFound if trigger is not pending
+func (c *Client) CompareAndReschedule(id string, expectedTime, newTime time.Time) (bool, error) {
+
+	key, err := c.c.HGet(c.indexKey, id).Result()
+	if err == redis.Nil {
+		return false, ErrNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to get key of the trigger: %v", err)
+	}
+
+	ts, err := c.getTriggers(key)
+	if err != nil {
+		return false, fmt.Errorf("unable to get triggers: %v", err)
+	}
+	var t *Trigger
+	for _, tt := range ts {
+		if tt.ID == id {
+			t = tt
+		}
+	}
+	if t == nil {
+		return false, nil
+	}
+	if !t.DateTime.Equal(expectedTime) {
+		return false, nil
+	}
+
+	next := *t
+	next.DateTime = newTime
+	return c.compareAndMove(key, t, &next)
+}
+
+// compareAndMove provides atomic replacing of the trigger stored
+// in the key by the next trigger. It returns false if the trigger
+// is not in the key or its changed concurrently
+func (c *Client) compareAndMove(key string, t, next *Trigger) (bool, error) {
+
+	encodedT, err := c.encode(next)
+	if err != nil {
+		return false, fmt.Errorf("unable to marshal trigger: %v", err)
+	}
+	nextKey, err := c.storeKey(next)
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := compareAndRescheduleScript.Run(c.c, []string{key, nextKey, c.indexKey},
+		t.raw, encodedT, t.ID, expireAtArg(next)).Int64()
+	if err != nil {
+		return false, c.closedOr(fmt.Errorf("unable to reschedule trigger: %v", err))
+	}
+	if ok == 0 {
+		return false, nil
+	}
+
+	c.moveResults(t.raw, string(encodedT))
+	c.notify(next)
+	return true, nil
+}
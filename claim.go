@@ -0,0 +1,201 @@
+package rc
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// leaseScript provides moving of the member from the key to the
+// in-flight hash of the worker with the lease deadline and claim time
+var leaseScript = redis.NewScript(`
+if redis.call("SREM", KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call("HDEL", KEYS[2], ARGV[2])
+redis.call("HSET", KEYS[3], ARGV[2], ARGV[1])
+redis.call("HSET", KEYS[4], ARGV[2], KEYS[1])
+redis.call("ZADD", KEYS[5], ARGV[3], ARGV[2])
+redis.call("SADD", KEYS[6], ARGV[4])
+redis.call("ZADD", KEYS[7], ARGV[5], ARGV[2])
+return 1
+`)
+
+// reclaimScript provides moving of triggers which leases are expired
+// or which are claimed before ARGV[1], if ARGV[3] is "1", from the
+// in-flight hash of the worker back to their keys
+var reclaimScript = redis.NewScript(`
+local by = KEYS[3]
+if ARGV[3] == "1" then
+	by = KEYS[6]
+end
+local ids = redis.call("ZRANGEBYSCORE", by, "-inf", ARGV[1])
+for _, id in ipairs(ids) do
+	local member = redis.call("HGET", KEYS[1], id)
+	local key = redis.call("HGET", KEYS[2], id)
+	if member and key then
+		redis.call("SADD", key, member)
+		redis.call("HSET", KEYS[4], id, key)
+	end
+	redis.call("HDEL", KEYS[1], id)
+	redis.call("HDEL", KEYS[2], id)
+	redis.call("ZREM", KEYS[3], id)
+	redis.call("ZREM", KEYS[6], id)
+end
+if redis.call("ZCARD", KEYS[3]) == 0 then
+	redis.call("SREM", KEYS[5], ARGV[2])
+end
+return #ids
+`)
+
+// workersKey returns key of the set of workers which have leases
+func (c *Client) workersKey() string {
+	return fmt.Sprintf("%s:workers", c.prefix)
+}
+
+// inflightKeys returns keys of the in-flight hash of the worker with
+// members by IDs, of the hash with their keys and of the sorted set
+// with lease deadlines
+func (c *Client) inflightKeys(workerID string) (string, string, string) {
+	inflight := fmt.Sprintf("%s:inflight:%s", c.prefix, workerID)
+	return inflight, inflight + ":keys", inflight + ":deadlines"
+}
+
+// claimedKey returns key of the sorted set
+// with claim times of in-flight triggers of the worker
+func (c *Client) claimedKey(workerID string) string {
+	return fmt.Sprintf("%s:inflight:%s:claimed", c.prefix, workerID)
+}
+
+// Claim provides leasing of up to max ready triggers to the worker for
+// the visibility duration without calling of handlers. Leased triggers
+// are moved from their keys to the in-flight set of the worker, so they
+// are not fired by Start and not claimed by other workers. Worker should
+// confirm processing of triggers by Ack before the lease is expired.
+// Otherwise they are moved back to their keys by the next Claim of
+// any worker and can be claimed again, so triggers are processed
+// at least once. Like PopReady, its intended for clients
+// which don't run Start
+func (c *Client) Claim(workerID string, max int, visibility time.Duration) (Triggers, error) {
+
+	if c.isClosed() {
+		return nil, ErrClosed
+	}
+	if _, err := c.reclaim(); err != nil {
+		return nil, err
+	}
+
+	inflight, keys, deadlines := c.inflightKeys(workerID)
+	now := c.now()
+	leaseUntil := strconv.FormatInt(toMillis(now.Add(visibility)), base10)
+	claimedAt := strconv.FormatInt(toMillis(now), base10)
+	return c.popReady(max, func(key string, t *Trigger) (bool, error) {
+		n, err := leaseScript.Run(c.c,
+			[]string{key, c.indexKey, inflight, keys, deadlines, c.workersKey(), c.claimedKey(workerID)},
+			t.raw, t.ID, leaseUntil, workerID, claimedAt).Int64()
+		if err != nil {
+			return false, c.closedOr(err)
+		}
+		return n == 1, nil
+	})
+}
+
+// Ack provides confirmation of processing of triggers leased by Claim,
+// so they are removed from the in-flight set of the worker. It returns
+// number of confirmed triggers, triggers which leases are expired
+// and reclaimed are not counted. Triggers which depend on confirmed
+// ones are released
+func (c *Client) Ack(workerID string, ids []string) (int, error) {
+
+	if c.isClosed() {
+		return 0, ErrClosed
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	inflight, keys, deadlines := c.inflightKeys(workerID)
+	members := make([]interface{}, len(ids))
+	for i, id := range ids {
+		members[i] = id
+	}
+	hDels := make([]*redis.IntCmd, len(ids))
+	_, err := c.c.TxPipelined(func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			hDels[i] = pipe.HDel(inflight, id)
+		}
+		pipe.HDel(keys, ids...)
+		pipe.ZRem(deadlines, members...)
+		pipe.ZRem(c.claimedKey(workerID), members...)
+		return nil
+	})
+	if err != nil {
+		return 0, c.closedOr(fmt.Errorf("unable to ack triggers: %v", err))
+	}
+
+	var acked int
+	for i, cmd := range hDels {
+		if cmd.Val() == 1 {
+			acked++
+			c.releaseDependents(ids[i])
+		}
+	}
+
+	return acked, nil
+}
+
+// RecoverStale provides moving of triggers which are claimed by
+// Claim longer than olderThan ago and are not acknowledged back
+// to their keys, even if their leases are not expired, e.g. after
+// crashes of workers with long visibility durations. Triggers which
+// time is passed are fired on the next check. Its safe to call while
+// workers are running, but trigger which is recovered while its
+// processed can be processed twice. It returns number of recovered
+// triggers
+func (c *Client) RecoverStale(olderThan time.Duration) (int, error) {
+
+	if c.isClosed() {
+		return 0, ErrClosed
+	}
+
+	return c.moveInflight(c.now().Add(-olderThan), true)
+}
+
+// reclaim provides moving of triggers which leases
+// are expired back to their keys
+func (c *Client) reclaim() (int, error) {
+	return c.moveInflight(c.now(), false)
+}
+
+// moveInflight provides moving of in-flight triggers of all workers
+// back to their keys which leases are expired before max or, if
+// byClaimed is set, which are claimed before max
+func (c *Client) moveInflight(max time.Time, byClaimed bool) (int, error) {
+
+	workers, err := c.c.SMembers(c.workersKey()).Result()
+	if err != nil {
+		return 0, c.closedOr(fmt.Errorf("unable to get workers: %v", err))
+	}
+
+	var reclaimed int
+	maxArg := strconv.FormatInt(toMillis(max), base10)
+	byArg := "0"
+	if byClaimed {
+		byArg = "1"
+	}
+	for _, w := range workers {
+		inflight, keys, deadlines := c.inflightKeys(w)
+		var n int64
+		n, err = reclaimScript.Run(c.c,
+			[]string{inflight, keys, deadlines, c.indexKey, c.workersKey(), c.claimedKey(w)},
+			maxArg, w, byArg).Int64()
+		if err != nil {
+			return reclaimed, c.closedOr(fmt.Errorf("unable to reclaim triggers: %v", err))
+		}
+		reclaimed += int(n)
+	}
+
+	return reclaimed, nil
+}
@@ -0,0 +1,47 @@
+package rc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecoverStale(t *testing.T) {
+	clock := &testClock{t: time.Now()}
+	c, _ := newTestClient(t, func(o *ClientOptions) {
+		o.Clock = clock.now
+	})
+	for _, id := range []string{"a", "b"} {
+		if err := c.AddTrigger(&Trigger{ID: id, Handler: "h", DateTime: clock.now().Add(-time.Second)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if ts, err := c.Claim("w1", 1, time.Hour); err != nil || len(ts) != 1 {
+		t.Fatalf("expected claimed trigger, got %v %v", ts, err)
+	}
+	clock.add(10 * time.Minute)
+	if ts, err := c.Claim("w2", 1, time.Hour); err != nil || len(ts) != 1 {
+		t.Fatalf("expected claimed trigger, got %v %v", ts, err)
+	}
+
+	n, err := c.RecoverStale(5 * time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 recovered trigger, got %d", n)
+	}
+	ts, err := c.Claim("w3", 2, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ts) != 1 {
+		t.Fatalf("expected recovered trigger to be claimed again, got %d", len(ts))
+	}
+	if n, err = c.Ack("w3", []string{ts[0].ID}); err != nil || n != 1 {
+		t.Fatalf("expected acked trigger, got %d %v", n, err)
+	}
+	if n, err = c.RecoverStale(0); err != nil || n != 1 {
+		t.Fatalf("expected only not acknowledged trigger to be recovered, got %d %v", n, err)
+	}
+}
@@ -0,0 +1,36 @@
+package rc
+
+import (
+	"log"
+	"time"
+)
+
+// serverTimeRefresh is a duration after which offset
+// between local and server time is updated
+const serverTimeRefresh = time.Minute
+
+// now returns current time for checking of ready triggers.
+// With UseServerTime its the local time shifted by the offset
+// to the Redis server time. Offset is requested with TIME command
+// and cached for serverTimeRefresh
+func (c *Client) now() time.Time {
+	local := c.clock()
+	if !c.useServerTime {
+		return local
+	}
+
+	c.clockMu.Lock()
+	defer c.clockMu.Unlock()
+
+	if c.clockSynced.IsZero() || local.Sub(c.clockSynced) >= serverTimeRefresh {
+		st, err := c.c.Time().Result()
+		if err != nil {
+			log.Printf("unable to get server time: %v", err)
+		} else {
+			c.clockOffset = st.Sub(c.clock())
+			c.clockSynced = local
+		}
+	}
+
+	return local.Add(c.clockOffset)
+}
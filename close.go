@@ -0,0 +1,72 @@
+package rc
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrClosed is returned when client is closed
+var ErrClosed = errors.New("client is closed")
+
+// Close provides stopping of Start and monitors, waiting until
+// they are finished and closing of the Redis client. Adding and removing
+// of triggers after Close or concurrently with it return ErrClosed.
+//
+// Close waits for running handlers until the context is done. Then
+// the Redis client is closed and ctx.Err() is returned without waiting.
+// Handlers which are still running are not interrupted, but their
+// triggers can't be removed after they are finished, so they are
+// left in their keys and fired again by the next Start
+func (c *Client) Close(ctx context.Context) error {
+	if !atomic.CompareAndSwapUint32(&c.closed, 0, 1) {
+		return ErrClosed
+	}
+
+	close(c.stop)
+	finished := make(chan struct{})
+	go func() {
+		if atomic.LoadUint32(&c.started) == 1 {
+			<-c.done
+		}
+		c.monitors.Wait()
+		close(finished)
+	}()
+
+	var err error
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if cErr := c.c.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
+
+func (c *Client) isClosed() bool {
+	return atomic.LoadUint32(&c.closed) == 1
+}
+
+// closedOr returns ErrClosed if client was closed
+// while the command was in flight, or err otherwise
+func (c *Client) closedOr(err error) error {
+	if c.isClosed() {
+		return ErrClosed
+	}
+	return err
+}
+
+// sleep provides sleeping for d or until client is closed
+func (c *Client) sleep(d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+	case <-c.stop:
+	}
+}
@@ -0,0 +1,58 @@
+package rc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAddTriggerConcurrentlyWithClose(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		errs   = map[error]int{}
+		start  = make(chan struct{})
+		closed = make(chan struct{})
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for j := 0; j < 50; j++ {
+				tr := &Trigger{Handler: "h", DateTime: time.Now().Add(time.Hour)}
+				err := c.AddTrigger(tr)
+				if err == nil {
+					err = c.RemoveTrigger(c.keyFunc(tr), tr)
+				}
+				mu.Lock()
+				errs[err]++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	go func() {
+		<-start
+		time.Sleep(time.Millisecond)
+		if err := c.Close(context.Background()); err != nil {
+			t.Error(err)
+		}
+		close(closed)
+	}()
+	close(start)
+	wg.Wait()
+	<-closed
+
+	for err, n := range errs {
+		if err != nil && err != ErrClosed {
+			t.Fatalf("expected only ErrClosed, got %d of %v", n, err)
+		}
+	}
+	if err := c.AddTrigger(&Trigger{Handler: "h", DateTime: time.Now()}); err != ErrClosed {
+		t.Fatalf("expected ErrClosed after Close, got %v", err)
+	}
+}
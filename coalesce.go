@@ -0,0 +1,71 @@
+package rc
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// coalesceRetention is a duration after the time of the key
+// during which its coalesce keys are kept
+const coalesceRetention = 24 * time.Hour
+
+// coalesceScript returns ID of the pending trigger of the key
+// with the same CoalesceKey. Otherwise trigger is registered
+// as the one of CoalesceKey and empty string is returned
+var coalesceScript = redis.NewScript(`
+local id = redis.call("HGET", KEYS[1], ARGV[1])
+if id then
+	local key = redis.call("HGET", KEYS[2], id)
+	if key and (key == ARGV[3] or string.sub(key, 1, #ARGV[3] + 1) == ARGV[3] .. ":") then
+		return id
+	end
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+redis.call("PEXPIREAT", KEYS[1], ARGV[4])
+redis.call("HSET", KEYS[2], ARGV[2], ARGV[3])
+return ""
+`)
+
+// coalesceKey returns key of the hash with IDs
+// of triggers of the key by their CoalesceKey
+func (c *Client) coalesceKey(key string) string {
+	return fmt.Sprintf("%s:coalesce:%s", c.prefix, key)
+}
+
+// addCoalesced provides inserting of the trigger with CoalesceKey
+// if there is no pending trigger of the same key with it.
+// Otherwise ID of the trigger is set to ID of the pending one
+// and false is returned
+func (c *Client) addCoalesced(t *Trigger, encodedT []byte) (string, bool, error) {
+
+	key := c.keyFunc(t)
+	expireAt := toMillis(t.scheduledTime().Add(coalesceRetention))
+	id, err := coalesceScript.Run(c.c, []string{c.coalesceKey(key), c.indexKey},
+		t.CoalesceKey, t.ID, key, strconv.FormatInt(expireAt, base10)).String()
+	if err != nil {
+		return "", false, c.closedOr(fmt.Errorf("unable to coalesce trigger: %v", err))
+	}
+	if id != "" {
+		t.ID = id
+		return key, false, nil
+	}
+
+	key, err = c.insert(t, encodedT)
+	if err != nil {
+		_, dErr := c.c.TxPipelined(func(pipe redis.Pipeliner) error {
+			pipe.HDel(c.coalesceKey(c.keyFunc(t)), t.CoalesceKey)
+			pipe.HDel(c.indexKey, t.ID)
+			return nil
+		})
+		if dErr != nil {
+			log.Printf("unable to remove coalesce key of the trigger: %v", dErr)
+		}
+		return "", false, err
+	}
+
+	return key, true, nil
+}
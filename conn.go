@@ -0,0 +1,37 @@
+package rc
+
+import (
+	"runtime"
+
+	"github.com/go-redis/redis"
+)
+
+const (
+	// defaultMinIdleConns is a number of connections which are kept
+	// open for the poll loop and notifications, so they are not
+	// dialed again after each interval
+	defaultMinIdleConns = 2
+	// reservedConns is a number of connections for the poll loop,
+	// monitors and adding of triggers in addition to handlers
+	reservedConns = 4
+)
+
+// poolOptions returns Redis options with defaults of the connection
+// pool for the scheduler, which are used if they are not set.
+// Each running handler uses a connection to remove or reschedule its
+// trigger, so the pool is not smaller than the number of concurrently
+// running handlers and the connections of the poll loop. Otherwise
+// handlers wait for connections and connections are dialed and closed
+// on each check under load
+func poolOptions(o redis.Options, concurrency int) *redis.Options {
+	if o.PoolSize == 0 {
+		o.PoolSize = 10 * runtime.NumCPU()
+		if n := concurrency + reservedConns; n > o.PoolSize {
+			o.PoolSize = n
+		}
+	}
+	if o.MinIdleConns == 0 {
+		o.MinIdleConns = defaultMinIdleConns
+	}
+	return &o
+}
@@ -0,0 +1,113 @@
+package rc
+
+import "context"
+
+// metaContextKey is a key of Meta of the trigger in the handler context
+type metaContextKey struct{}
+
+// MetaFromContext returns Meta of the trigger from the context of its
+// handler, see RegisterContextHandler. It returns nil if Meta is not set
+func MetaFromContext(ctx context.Context) map[string]string {
+	meta, _ := ctx.Value(metaContextKey{}).(map[string]string)
+	return meta
+}
+
+func contextWithMeta(ctx context.Context, meta map[string]string) context.Context {
+	if meta == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, metaContextKey{}, meta)
+}
+
+// runningHandler defines the handler which is running
+type runningHandler struct {
+	cancel context.CancelFunc
+}
+
+// RegisterContextHandler provides registration of the handler by the
+// name as RegisterTriggerHandler, but the handler gets the context
+// which is cancelled when the trigger is cancelled by Cancel or
+// CancelMany of this client while the handler is running. Handlers
+// registered without the context can't be interrupted
+func (c *Client) RegisterContextHandler(name string, fn func(ctx context.Context, t *Trigger) error) {
+	c.methodsMu.Lock()
+	defer c.methodsMu.Unlock()
+	c.methods[name] = fn
+}
+
+// startRunning returns the context of the handler of the trigger
+// and the function which must be called when its finished
+func (c *Client) startRunning(parent context.Context, id string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	h := &runningHandler{cancel: cancel}
+
+	c.runningMu.Lock()
+	c.running[id] = h
+	c.runningMu.Unlock()
+
+	return ctx, func() {
+		c.runningMu.Lock()
+		if c.running[id] == h {
+			delete(c.running, id)
+		}
+		c.runningMu.Unlock()
+		cancel()
+	}
+}
+
+// isRunning returns true if handler of the trigger is running
+func (c *Client) isRunning(id string) bool {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+	_, ok := c.running[id]
+	return ok
+}
+
+// cancelRunning provides cancelling of the context of the running
+// handler of the trigger. It returns false if its not running
+func (c *Client) cancelRunning(id string) bool {
+	c.runningMu.Lock()
+	h, ok := c.running[id]
+	c.runningMu.Unlock()
+
+	if ok {
+		h.cancel()
+	}
+	return ok
+}
+
+// AddTriggerContext provides adding of the new trigger as AddTrigger,
+// but it returns ctx.Err() if the context is done before the trigger
+// is stored. The trigger is not stored if the context is done before
+// its checks are finished. If its done while the trigger is being
+// stored, the command may be already sent to Redis, so the trigger
+// can still be added. The trigger is changed only if its added
+func (c *Client) AddTriggerContext(ctx context.Context, t *Trigger) error {
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	nt := *t
+	errc := make(chan error, 1)
+	go func() {
+		encodedT, err := c.encodeTrigger(&nt)
+		if err == nil {
+			if err = ctx.Err(); err == nil {
+				_, err = c.add(&nt, encodedT)
+			}
+		}
+		errc <- err
+	}()
+
+	select {
+	case err := <-errc:
+		if err != nil {
+			return err
+		}
+		*t = nt
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
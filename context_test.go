@@ -0,0 +1,32 @@
+package rc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAddTriggerContextCancelledIsNotStored(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, _ := newTestClient(t, func(o *ClientOptions) {
+		o.RejectPast = true
+		o.Clock = func() time.Time {
+			cancel()
+			return time.Now()
+		}
+	})
+
+	tr := &Trigger{Handler: "h", DateTime: time.Now().Add(time.Hour)}
+	if err := c.AddTriggerContext(ctx, tr); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if tr.ID != "" {
+		t.Fatalf("expected not changed trigger, got ID %s", tr.ID)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if n, err := c.countPending(); err != nil || n != 0 {
+		t.Fatalf("expected no stored triggers, got %d %v", n, err)
+	}
+}
@@ -0,0 +1,218 @@
+package rc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears is a maximum number of years in which
+// the next occurrence of the cron spec is searched
+const cronSearchYears = 5
+
+// cronAliases defines predefined cron specs
+var cronAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSchedule defines parsed standard cron spec with fields
+// minute, hour, day of month, month and day of week
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+// AddCron provides adding of the recurring trigger by the cron spec.
+// Its first occurrence is the next one from now, then it's
+// scheduled by the spec after each firing. It returns ID of the trigger
+func (c *Client) AddCron(spec, namespace, handler string) (string, error) {
+
+	t := &Trigger{
+		Namespace: namespace,
+		Handler:   handler,
+		CronSpec:  spec,
+	}
+	next, err := c.cronNext(t)
+	if err != nil {
+		return "", err
+	}
+	t.DateTime = next(c.now())
+	if t.DateTime.IsZero() {
+		return "", fmt.Errorf("cron spec %q has no next occurrence", spec)
+	}
+	if err = c.AddTrigger(t); err != nil {
+		return "", err
+	}
+
+	return t.ID, nil
+}
+
+// ValidateCron returns error if the cron spec can't be parsed
+// by AddCron and CronSpec of triggers or has no occurrences,
+// e.g. "0 0 30 2 *"
+func ValidateCron(spec string) error {
+	s, err := parseCron(spec)
+	if err != nil {
+		return err
+	}
+	if s.next(time.Now()).IsZero() {
+		return fmt.Errorf("invalid cron spec %q: it has no occurrences", spec)
+	}
+	return nil
+}
+
+// cronNext returns function of the next occurrence by CronSpec
+// of the trigger which is evaluated in its CronLocation or
+// in CronLocation of the client
+func (c *Client) cronNext(t *Trigger) (func(time.Time) time.Time, error) {
+	s, err := parseCron(t.CronSpec)
+	if err != nil {
+		return nil, err
+	}
+	loc := c.cronLocation
+	if t.CronLocation != "" {
+		if loc, err = time.LoadLocation(t.CronLocation); err != nil {
+			return nil, fmt.Errorf("invalid cron location %q: %v", t.CronLocation, err)
+		}
+	}
+	return func(prev time.Time) time.Time {
+		return s.next(prev.In(loc))
+	}, nil
+}
+
+// parseCron returns schedule by the cron spec
+func parseCron(spec string) (*cronSchedule, error) {
+
+	expr := strings.TrimSpace(spec)
+	if alias, ok := cronAliases[expr]; ok {
+		expr = alias
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron spec %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	var (
+		s   cronSchedule
+		err error
+	)
+	bounds := []struct {
+		name     string
+		field    *uint64
+		min, max int
+	}{
+		{"minute", &s.minute, 0, 59},
+		{"hour", &s.hour, 0, 23},
+		{"day of month", &s.dom, 1, 31},
+		{"month", &s.month, 1, 12},
+		{"day of week", &s.dow, 0, 7},
+	}
+	for i, b := range bounds {
+		*b.field, err = parseCronField(fields[i], b.min, b.max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron spec %q: %s: %v", spec, b.name, err)
+		}
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+
+	return &s, nil
+}
+
+// parseCronField returns bits of values of the cron field
+// which is a list of values, ranges and steps
+func parseCronField(field string, min, max int) (uint64, error) {
+
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			rng = part[:i]
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = n
+		}
+
+		from, to := min, max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			i := strings.Index(rng, "-")
+			var err error
+			if from, err = strconv.Atoi(rng[:i]); err != nil {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+			if to, err = strconv.Atoi(rng[i+1:]); err != nil {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			n, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			from, to = n, n
+			if step > 1 {
+				to = max
+			}
+		}
+		if from < min || to > max || from > to {
+			return 0, fmt.Errorf("value of %q is out of range %d-%d", part, min, max)
+		}
+
+		for v := from; v <= to; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// next returns the first occurrence of the schedule after t
+// in the location of t. It returns zero time if there is no
+// occurrence in the next cronSearchYears years
+func (s *cronSchedule) next(t time.Time) time.Time {
+
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + cronSearchYears
+	for t.Year() <= limit {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchDay returns true if day of t matches the schedule. As in the
+// standard cron, if both day of month and day of week are restricted,
+// day matches any of them
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
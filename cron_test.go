@@ -0,0 +1,46 @@
+package rc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNextKeepsLocalTimeAfterDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	c, _ := newTestClient(t, func(o *ClientOptions) {
+		o.CronLocation = berlin
+	})
+
+	// 9:00 before the transition to summer time on 2024-03-31,
+	// decoded from JSON with the fixed offset
+	tr, err := c.decode(`{"id":"a","handler":"h","cron_spec":"0 9 * * *","date_time":"2024-03-30T09:00:00+01:00"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, ok := c.nextFunc(tr)
+	if !ok || next == nil {
+		t.Fatal("expected next function of the cron spec")
+	}
+	got := next(tr.DateTime).In(berlin)
+	want := time.Date(2024, 3, 31, 9, 0, 0, 0, berlin)
+	if !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	tr.CronLocation = "UTC"
+	next, _ = c.nextFunc(tr)
+	if got = next(tr.DateTime); !got.Equal(time.Date(2024, 3, 30, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected occurrence in UTC, got %v", got)
+	}
+}
+
+func TestAddCronInvalidLocation(t *testing.T) {
+	c, _ := newTestClient(t)
+	err := c.AddTrigger(&Trigger{Handler: "h", DateTime: time.Now(), CronSpec: "* * * * *", CronLocation: "Nowhere/City"})
+	if err == nil {
+		t.Fatal("expected error of the invalid cron location")
+	}
+}
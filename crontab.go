@@ -0,0 +1,99 @@
+package rc
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LoadCrontab provides adding of recurring triggers by lines of
+// the crontab-like file. Line has the form
+// <cron spec> <handler> [payload], where cron spec is a standard
+// cron spec of 5 fields or an alias like @daily and payload is
+// a JSON. Blank lines and lines started with # are skipped.
+// All lines are parsed before adding, so nothing is added if
+// some line is invalid. ID of the trigger is made by the line,
+// so triggers which are already pending are not added again,
+// and the file can be loaded on each start. It returns number
+// of added triggers
+func (c *Client) LoadCrontab(r io.Reader) (int, error) {
+
+	var ts []*Trigger
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		t, err := parseCrontabLine(line)
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %v", n, err)
+		}
+		ts = append(ts, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("unable to read crontab: %v", err)
+	}
+
+	var loaded int
+	for _, t := range ts {
+		ok, err := c.isPending(t.ID)
+		if err != nil {
+			return loaded, fmt.Errorf("unable to check trigger: %v", err)
+		}
+		if ok {
+			continue
+		}
+
+		next, nErr := c.cronNext(t)
+		if nErr != nil {
+			return loaded, nErr
+		}
+		t.DateTime = next(c.now())
+		if t.DateTime.IsZero() {
+			return loaded, fmt.Errorf("cron spec %q has no next occurrence", t.CronSpec)
+		}
+		if err = c.AddTrigger(t); err != nil {
+			return loaded, err
+		}
+		loaded++
+	}
+
+	return loaded, nil
+}
+
+// parseCrontabLine returns recurring trigger by the line of crontab
+func parseCrontabLine(line string) (*Trigger, error) {
+
+	fields := strings.Fields(line)
+	specFields := 5
+	if strings.HasPrefix(line, "@") {
+		specFields = 1
+	}
+	if len(fields) <= specFields {
+		return nil, fmt.Errorf("handler is not defined")
+	}
+
+	spec := strings.Join(fields[:specFields], " ")
+	if _, err := parseCron(spec); err != nil {
+		return nil, err
+	}
+
+	t := &Trigger{
+		Handler:  fields[specFields],
+		CronSpec: spec,
+	}
+	if len(fields) > specFields+1 {
+		payload := strings.Join(fields[specFields+1:], " ")
+		if !json.Valid([]byte(payload)) {
+			return nil, fmt.Errorf("payload is not a valid JSON")
+		}
+		t.Payload = json.RawMessage(payload)
+	}
+	t.ID = fmt.Sprintf("crontab-%x", sha1.Sum([]byte(strings.Join(fields, " "))))
+
+	return t, nil
+}
@@ -0,0 +1,136 @@
+package rc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// encryptedPrefix is a header of the encrypted payload.
+// Its followed by the base64 of the key version,
+// the nonce and the sealed payload
+const encryptedPrefix = "rcenc1:"
+
+// payloadCrypt provides AES-GCM encryption of payloads
+// by the keys of EncryptionKeys
+type payloadCrypt struct {
+	version byte
+	aeads   map[byte]cipher.AEAD
+}
+
+func newPayloadCrypt(keys map[byte][]byte, version byte) (*payloadCrypt, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	if _, ok := keys[version]; !ok {
+		return nil, fmt.Errorf("EncryptionKeys doesn't contain key of EncryptionKeyVersion %d", version)
+	}
+
+	p := &payloadCrypt{version: version, aeads: map[byte]cipher.AEAD{}}
+	for v, k := range keys {
+		block, err := aes.NewCipher(k)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create cipher of the key %d: %v", v, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create cipher of the key %d: %v", v, err)
+		}
+		p.aeads[v] = aead
+	}
+	return p, nil
+}
+
+// seal returns payload encrypted by the current key as JSON string.
+// Already encrypted payload is returned as is
+func (p *payloadCrypt) seal(payload json.RawMessage) (json.RawMessage, error) {
+	if len(payload) == 0 || isEncrypted(payload) {
+		return payload, nil
+	}
+
+	aead := p.aeads[p.version]
+	b := make([]byte, 1+aead.NonceSize(), 1+aead.NonceSize()+len(payload)+aead.Overhead())
+	b[0] = p.version
+	if _, err := rand.Read(b[1:]); err != nil {
+		return nil, fmt.Errorf("unable to generate nonce: %v", err)
+	}
+	b = aead.Seal(b, b[1:], payload, nil)
+
+	return json.Marshal(encryptedPrefix + base64.StdEncoding.EncodeToString(b))
+}
+
+// open returns decrypted payload. Not encrypted payload is returned as is
+func (p *payloadCrypt) open(payload json.RawMessage) (json.RawMessage, error) {
+	if !isEncrypted(payload) {
+		return payload, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(payload, &s); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal encrypted payload: %v", err)
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, encryptedPrefix))
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("unable to decode encrypted payload: %v", err)
+	}
+	aead, ok := p.aeads[b[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown key version %d of the encrypted payload", b[0])
+	}
+	if len(b) < 1+aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted payload is too short")
+	}
+	nonce, sealed := b[1:1+aead.NonceSize()], b[1+aead.NonceSize():]
+	r, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt payload: %v", err)
+	}
+	return r, nil
+}
+
+// isEncrypted returns true if payload is a JSON string with encryptedPrefix
+func isEncrypted(payload json.RawMessage) bool {
+	return strings.HasPrefix(string(payload), `"`+encryptedPrefix)
+}
+
+// encode provides marshaling of the trigger
+// with the payload encrypted if EncryptionKeys is set
+func (c *Client) encode(t *Trigger) ([]byte, error) {
+	t, err := c.sealed(t)
+	if err != nil {
+		return nil, err
+	}
+	return t.encode()
+}
+
+// sealed returns copy of the trigger with the encrypted payload
+// or the trigger itself if EncryptionKeys is not set
+func (c *Client) sealed(t *Trigger) (*Trigger, error) {
+	if c.crypt == nil {
+		return t, nil
+	}
+	payload, err := c.crypt.seal(t.Payload)
+	if err != nil {
+		return nil, err
+	}
+	st := *t
+	st.Payload = payload
+	return &st, nil
+}
+
+// openPayload provides decrypting of the payload of the decoded trigger
+func (c *Client) openPayload(t *Trigger) error {
+	if c.crypt == nil || t == nil {
+		return nil
+	}
+	payload, err := c.crypt.open(t.Payload)
+	if err != nil {
+		return err
+	}
+	t.Payload = payload
+	return nil
+}
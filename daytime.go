@@ -0,0 +1,48 @@
+package rc
+
+import (
+	"fmt"
+	"time"
+)
+
+// AddAtNextMidnight provides adding of the trigger which is scheduled
+// on the next midnight in the location, see AddAtNextTimeOfDay
+func (c *Client) AddAtNextMidnight(loc *time.Location, t *Trigger) error {
+	return c.AddAtNextTimeOfDay(loc, 0, 0, t)
+}
+
+// AddAtNextTimeOfDay provides adding of the trigger which is scheduled
+// on the next hour:min in the location after the current time of the
+// client, so all clients get the same time regardless of their local
+// zones. DateTime of the trigger is set in UTC. Time which is skipped
+// by the DST transition is moved forward by the transition
+func (c *Client) AddAtNextTimeOfDay(loc *time.Location, hour, min int, t *Trigger) error {
+	if loc == nil {
+		return fmt.Errorf("location is not defined")
+	}
+	if hour < 0 || hour > 23 || min < 0 || min > 59 {
+		return fmt.Errorf("invalid time of day %02d:%02d", hour, min)
+	}
+
+	t.DateTime = nextTimeOfDay(c.now(), loc, hour, min).UTC()
+	return c.AddTrigger(t)
+}
+
+// nextTimeOfDay returns the first hour:min
+// in the location after now
+func nextTimeOfDay(now time.Time, loc *time.Location, hour, min int) time.Time {
+	local := now.In(loc)
+	for d := 0; ; d++ {
+		next := time.Date(local.Year(), local.Month(), local.Day()+d, hour, min, 0, 0, loc)
+		if next.Hour() != hour || next.Minute() != min {
+			// time is skipped by the transition, so its
+			// moved forward by the change of the offset
+			_, offset := next.Zone()
+			_, after := next.Add(3 * time.Hour).Zone()
+			next = next.Add(time.Duration(after-offset) * time.Second)
+		}
+		if next.After(now) {
+			return next
+		}
+	}
+}
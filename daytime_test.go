@@ -0,0 +1,66 @@
+package rc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextTimeOfDayDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	for name, tc := range map[string]struct {
+		now       time.Time
+		hour, min int
+		want      time.Time
+	}{
+		"midnight before spring forward": {
+			now:  time.Date(2024, 3, 30, 12, 0, 0, 0, berlin),
+			want: time.Date(2024, 3, 30, 23, 0, 0, 0, time.UTC),
+		},
+		"midnight after spring forward": {
+			now:  time.Date(2024, 3, 31, 12, 0, 0, 0, berlin),
+			want: time.Date(2024, 3, 31, 22, 0, 0, 0, time.UTC),
+		},
+		"skipped time is moved forward": {
+			now:  time.Date(2024, 3, 31, 0, 0, 0, 0, berlin),
+			hour: 2, min: 30,
+			want: time.Date(2024, 3, 31, 1, 30, 0, 0, time.UTC),
+		},
+		"midnight after fall back": {
+			now:  time.Date(2024, 10, 27, 12, 0, 0, 0, berlin),
+			want: time.Date(2024, 10, 27, 23, 0, 0, 0, time.UTC),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := nextTimeOfDay(tc.now, berlin, tc.hour, tc.min)
+			if !got.Equal(tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, got.UTC())
+			}
+		})
+	}
+}
+
+func TestAddAtNextMidnightInUTC(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	now := time.Date(2024, 3, 30, 12, 0, 0, 0, berlin)
+	c, _ := newTestClient(t, func(o *ClientOptions) {
+		o.Clock = func() time.Time { return now }
+	})
+
+	tr := &Trigger{Handler: "h"}
+	if err = c.AddAtNextMidnight(berlin, tr); err != nil {
+		t.Fatal(err)
+	}
+	if tr.DateTime.Location() != time.UTC {
+		t.Fatalf("expected time in UTC, got %v", tr.DateTime.Location())
+	}
+	if want := time.Date(2024, 3, 30, 23, 0, 0, 0, time.UTC); !tr.DateTime.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, tr.DateTime)
+	}
+}
@@ -0,0 +1,105 @@
+package rc
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+const (
+	// ReasonExpired is a reason of the trigger which
+	// was not fired before its Deadline
+	ReasonExpired = "expired"
+	// ReasonStale is a reason of the trigger which
+	// was ready later than its MaxStaleness
+	ReasonStale = "stale"
+	// ReasonFailed is a reason of the trigger which
+	// handler is failed after all retries
+	ReasonFailed = "failed"
+	// ReasonRescheduleFailed is a reason of the recurring trigger
+	// which next occurrence can't be scheduled
+	ReasonRescheduleFailed = "reschedule_failed"
+)
+
+// DeadTrigger defines a trigger which was moved
+// to the dead-letter set instead of firing
+type DeadTrigger struct {
+	Trigger *Trigger  `json:"trigger"`
+	Reason  string    `json:"reason"`
+	Time    time.Time `json:"time"`
+}
+
+// ListDead returns triggers from the dead-letter set
+func (c *Client) ListDead() ([]*DeadTrigger, error) {
+
+	sCmd := c.c.SMembers(c.deadKey)
+	if sCmd.Err() != nil {
+		return nil, fmt.Errorf("unable to get dead triggers: %v", sCmd.Err())
+	}
+
+	var r []*DeadTrigger
+	for _, v := range sCmd.Val() {
+		d := &DeadTrigger{}
+		if err := json.Unmarshal([]byte(v), d); err != nil {
+			continue
+		}
+		if err := c.openPayload(d.Trigger); err != nil {
+			continue
+		}
+		r = append(r, d)
+	}
+
+	return r, nil
+}
+
+// skip provides removing of the ready trigger without firing.
+// Trigger is moved to the dead-letter set unless drop is true
+func (c *Client) skip(key string, t *Trigger, reason string, drop bool) {
+	if drop {
+		if err := c.remove(key, t.raw, t.ID); err != nil {
+			log.Printf("unable to remove %s trigger: %v", reason, err)
+		}
+	} else {
+		c.moveToDead(key, t, reason)
+	}
+
+	c.metrics.IncCounter(MetricTriggersSkipped, map[string]string{"namespace": t.Namespace, "reason": reason})
+	if c.onSkip != nil {
+		c.onSkip(t, reason)
+	}
+}
+
+// moveToDead provides moving of the trigger from the key
+// to the dead-letter set with the reason
+func (c *Client) moveToDead(key string, t *Trigger, reason string) {
+
+	st, err := c.sealed(t)
+	if err != nil {
+		log.Printf("unable to encrypt dead trigger: %v", err)
+		return
+	}
+	d, err := json.Marshal(&DeadTrigger{
+		Trigger: st,
+		Reason:  reason,
+		Time:    c.now().UTC(),
+	})
+	if err != nil {
+		log.Printf("unable to marshal dead trigger: %v", err)
+		return
+	}
+
+	_, err = c.c.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.SRem(key, t.raw)
+		pipe.HDel(c.indexKey, t.ID)
+		pipe.SAdd(c.deadKey, d)
+		return nil
+	})
+	if err != nil {
+		log.Printf("unable to move trigger to the dead-letter set: %v", err)
+		return
+	}
+	c.metrics.IncCounter(MetricTriggersDead, map[string]string{"namespace": t.Namespace, "reason": reason})
+}
@@ -0,0 +1,133 @@
+package rc
+
+import (
+	"testing"
+	"time"
+)
+
+// newWindowClient returns client with the test clock
+// and the handler "h" which records IDs of fired triggers
+func newWindowClient(t *testing.T, opts ...Option) (*Client, *testClock, *[]string) {
+	t.Helper()
+	clock := &testClock{t: time.Now().Truncate(time.Second)}
+	c, _ := newTestClient(t, append([]Option{func(o *ClientOptions) {
+		o.Clock = clock.now
+	}}, opts...)...)
+	fired := &[]string{}
+	c.RegisterTriggerHandler("h", func(t *Trigger) error {
+		*fired = append(*fired, t.ID)
+		return nil
+	})
+	return c, clock, fired
+}
+
+func TestEarliestStartWindow(t *testing.T) {
+	c, clock, fired := newWindowClient(t)
+	now := clock.now()
+	tr := &Trigger{
+		ID:            "a",
+		Handler:       "h",
+		DateTime:      now,
+		EarliestStart: now.Add(time.Minute),
+		Deadline:      now.Add(time.Hour),
+	}
+	if err := c.AddTrigger(tr); err != nil {
+		t.Fatal(err)
+	}
+
+	// early
+	if _, err := c.ProcessOnce(); err != nil {
+		t.Fatal(err)
+	}
+	if len(*fired) != 0 {
+		t.Fatalf("expected trigger not to be fired before EarliestStart, got %v", *fired)
+	}
+
+	// on time
+	clock.add(time.Minute)
+	if _, err := c.ProcessOnce(); err != nil {
+		t.Fatal(err)
+	}
+	if len(*fired) != 1 {
+		t.Fatalf("expected trigger to be fired after EarliestStart, got %v", *fired)
+	}
+}
+
+func TestDeadlineExpired(t *testing.T) {
+	var skipped []string
+	c, clock, fired := newWindowClient(t, func(o *ClientOptions) {
+		o.OnSkip = func(_ *Trigger, reason string) { skipped = append(skipped, reason) }
+	})
+	now := clock.now()
+	tr := &Trigger{
+		ID:            "a",
+		Handler:       "h",
+		DateTime:      now,
+		EarliestStart: now.Add(time.Minute),
+		Deadline:      now.Add(2 * time.Minute),
+	}
+	if err := c.AddTrigger(tr); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.add(3 * time.Minute)
+	res, err := c.ProcessOnce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*fired) != 0 || res.Skipped != 1 {
+		t.Fatalf("expected expired trigger not to be fired, got %v", *fired)
+	}
+	if len(skipped) != 1 || skipped[0] != ReasonExpired {
+		t.Fatalf("expected OnSkip with %q, got %v", ReasonExpired, skipped)
+	}
+	dead, err := c.ListDead()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 1 || dead[0].Trigger.ID != "a" || dead[0].Reason != ReasonExpired {
+		t.Fatalf("expected expired trigger in the dead-letter set, got %v", dead)
+	}
+}
+
+func TestMaxStaleness(t *testing.T) {
+	for _, drop := range []bool{false, true} {
+		var skipped []string
+		c, clock, fired := newWindowClient(t, func(o *ClientOptions) {
+			o.DropStale = drop
+			o.OnSkip = func(_ *Trigger, reason string) { skipped = append(skipped, reason) }
+		})
+		now := clock.now()
+		for _, tr := range []*Trigger{
+			{ID: "on-time", Handler: "h", DateTime: now.Add(-time.Second), MaxStaleness: time.Minute},
+			{ID: "stale", Handler: "h", DateTime: now.Add(-time.Hour), MaxStaleness: time.Minute},
+		} {
+			if err := c.AddTrigger(tr); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if _, err := c.ProcessOnce(); err != nil {
+			t.Fatal(err)
+		}
+		if len(*fired) != 1 || (*fired)[0] != "on-time" {
+			t.Fatalf("expected only on-time trigger to be fired, got %v", *fired)
+		}
+		if len(skipped) != 1 || skipped[0] != ReasonStale {
+			t.Fatalf("expected OnSkip with %q, got %v", ReasonStale, skipped)
+		}
+		dead, err := c.ListDead()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if drop && len(dead) != 0 {
+			t.Fatalf("expected stale trigger to be dropped, got %v", dead)
+		}
+		if !drop && (len(dead) != 1 || dead[0].Reason != ReasonStale) {
+			t.Fatalf("expected stale trigger in the dead-letter set, got %v", dead)
+		}
+		if _, err = c.Get("stale"); err != ErrNotFound {
+			t.Fatalf("expected stale trigger to be removed, got %v", err)
+		}
+	}
+}
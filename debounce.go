@@ -0,0 +1,107 @@
+package rc
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis"
+)
+
+// commitDebouncedScript provides moving of the debounced trigger
+// to its key if the debounce window is passed and the trigger
+// was not replaced after it was read
+var commitDebouncedScript = redis.NewScript(`
+local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if not score or tonumber(score) > tonumber(ARGV[2]) then
+	return 0
+end
+if redis.call("HGET", KEYS[2], ARGV[1]) ~= ARGV[3] then
+	return 0
+end
+redis.call("ZREM", KEYS[1], ARGV[1])
+redis.call("HDEL", KEYS[2], ARGV[1])
+redis.call("SADD", KEYS[3], ARGV[3])
+if ARGV[5] ~= "0" then
+	redis.call("PEXPIREAT", KEYS[3], ARGV[5])
+end
+redis.call("HSET", KEYS[4], ARGV[4], KEYS[3])
+return 1
+`)
+
+// debounceKeys returns keys of the hash with debounced triggers
+// by DedupKey and of the sorted set with their commit times
+func (c *Client) debounceKeys() (string, string) {
+	return fmt.Sprintf("%s:debounce", c.prefix), fmt.Sprintf("%s:debounce:at", c.prefix)
+}
+
+// addDebounced provides holding of the trigger for the debounce window.
+// Previous trigger with the same DedupKey is replaced
+func (c *Client) addDebounced(t *Trigger, encodedT []byte) error {
+
+	hashKey, atKey := c.debounceKeys()
+	commitAt := c.now().Add(c.debounce)
+	_, err := c.c.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.HSet(hashKey, t.DedupKey, encodedT)
+		pipe.ZAdd(atKey, redis.Z{
+			Score:  float64(toMillis(commitAt)),
+			Member: t.DedupKey,
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to insert debounced trigger: %v", err)
+	}
+
+	return nil
+}
+
+// commitDebounced provides inserting of debounced triggers
+// which windows are passed
+func (c *Client) commitDebounced() error {
+
+	_, atKey := c.debounceKeys()
+	now := strconv.FormatInt(toMillis(c.now()), base10)
+	dedupKeys, err := c.c.ZRangeByScore(atKey, redis.ZRangeBy{Min: "-inf", Max: now}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, dk := range dedupKeys {
+		if err = c.commitDebouncedKey(dk, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// commitDebouncedKey provides inserting of the debounced
+// trigger by the DedupKey
+func (c *Client) commitDebouncedKey(dk, now string) error {
+
+	hashKey, atKey := c.debounceKeys()
+	member, err := c.c.HGet(hashKey, dk).Result()
+	if err == redis.Nil {
+		return c.c.ZRem(atKey, dk).Err()
+	}
+	if err != nil {
+		return err
+	}
+
+	t, err := c.decode(member)
+	if err != nil {
+		_, err = c.c.TxPipelined(func(pipe redis.Pipeliner) error {
+			pipe.ZRem(atKey, dk)
+			pipe.HDel(hashKey, dk)
+			return nil
+		})
+		return err
+	}
+
+	key, err := c.storeKey(t)
+	if err != nil {
+		return err
+	}
+	return commitDebouncedScript.Run(c.c, []string{atKey, hashKey, key, c.indexKey},
+		dk, now, member, t.ID, expireAtArg(t)).Err()
+}
@@ -0,0 +1,179 @@
+package rc
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/go-redis/redis"
+)
+
+// ErrDependencyCycle is returned when trigger depends
+// on itself directly or through other triggers
+var ErrDependencyCycle = errors.New("trigger dependencies have a cycle")
+
+// releaseDependentScript provides removing of the completed
+// dependency of the waiting trigger. It returns the waiting trigger
+// if it has no more dependencies
+var releaseDependentScript = redis.NewScript(`
+redis.call("SREM", KEYS[1], ARGV[1])
+if redis.call("SCARD", KEYS[1]) > 0 then
+	return false
+end
+local t = redis.call("HGET", KEYS[2], ARGV[2])
+if not t then
+	return false
+end
+redis.call("HDEL", KEYS[2], ARGV[2])
+return t
+`)
+
+// addDependentScript provides holding of the trigger by its pending
+// dependencies, which are checked and registered atomically, so the
+// dependency can't be fired between them. Dependency is pending if its
+// key exists or its waiting itself. It returns number of pending
+// dependencies, trigger is not held if there are no such ones
+var addDependentScript = redis.NewScript(`
+local pending = 0
+for i = 3, #ARGV do
+	local key = redis.call("HGET", KEYS[1], ARGV[i])
+	if (key and redis.call("EXISTS", key) == 1) or redis.call("HEXISTS", KEYS[2], ARGV[i]) == 1 then
+		redis.call("SADD", KEYS[3], ARGV[i])
+		redis.call("SADD", KEYS[i + 1], ARGV[1])
+		pending = pending + 1
+	end
+end
+if pending > 0 then
+	redis.call("HSET", KEYS[2], ARGV[1], ARGV[2])
+end
+return pending
+`)
+
+// waitingKey returns key of the hash with triggers
+// which are waiting for their dependencies
+func (c *Client) waitingKey() string {
+	return fmt.Sprintf("%s:waiting", c.prefix)
+}
+
+// dependenciesKey returns key of the set with not completed
+// dependencies of the waiting trigger
+func (c *Client) dependenciesKey(id string) string {
+	return fmt.Sprintf("%s:deps:%s", c.prefix, id)
+}
+
+// dependentsKey returns key of the set with
+// triggers which are waiting for the trigger
+func (c *Client) dependentsKey(id string) string {
+	return fmt.Sprintf("%s:dependents:%s", c.prefix, id)
+}
+
+// addDependent provides holding of the trigger until all its
+// dependencies are completed. Dependencies which are not pending
+// or waiting are considered as completed. Dependency is completed
+// when its handler succeeds, when its cancelled, popped by PopReady
+// or acknowledged by Ack. Dependency which is moved to the dead-letter
+// set is not completed, so its dependents are held until its requeued
+// and succeeds
+func (c *Client) addDependent(t *Trigger, encodedT []byte) error {
+
+	if err := c.checkCycle(t); err != nil {
+		return err
+	}
+
+	keys := []string{c.indexKey, c.waitingKey(), c.dependenciesKey(t.ID)}
+	args := []interface{}{t.ID, encodedT}
+	for _, id := range t.DependsOn {
+		keys = append(keys, c.dependentsKey(id))
+		args = append(args, id)
+	}
+	pending, err := addDependentScript.Run(c.c, keys, args...).Int64()
+	if err != nil {
+		return c.closedOr(fmt.Errorf("unable to insert waiting trigger: %v", err))
+	}
+	if pending == 0 {
+		_, err = c.insert(t, encodedT)
+		return err
+	}
+
+	return nil
+}
+
+// isPending returns true if trigger is scheduled
+// or waiting for its dependencies
+func (c *Client) isPending(id string) (bool, error) {
+	ok, err := c.isIndexed(id)
+	if err != nil || ok {
+		return ok, err
+	}
+	return c.c.HExists(c.waitingKey(), id).Result()
+}
+
+// checkCycle returns ErrDependencyCycle if trigger is reachable
+// from itself by not completed dependencies
+func (c *Client) checkCycle(t *Trigger) error {
+
+	visited := map[string]struct{}{}
+	ids := append([]string{}, t.DependsOn...)
+	for len(ids) > 0 {
+		id := ids[len(ids)-1]
+		ids = ids[:len(ids)-1]
+		if id == t.ID {
+			return ErrDependencyCycle
+		}
+		if _, ok := visited[id]; ok {
+			continue
+		}
+		visited[id] = struct{}{}
+
+		deps, err := c.c.SMembers(c.dependenciesKey(id)).Result()
+		if err != nil {
+			return fmt.Errorf("unable to get dependencies: %v", err)
+		}
+		ids = append(ids, deps...)
+	}
+
+	return nil
+}
+
+// releaseDependents provides scheduling of triggers
+// which are waiting only for the completed trigger
+func (c *Client) releaseDependents(id string) {
+
+	// dependents are read and removed atomically, so triggers
+	// which are added after that are not held by the trigger
+	key := c.dependentsKey(id)
+	var sMembers *redis.StringSliceCmd
+	_, err := c.c.TxPipelined(func(pipe redis.Pipeliner) error {
+		sMembers = pipe.SMembers(key)
+		pipe.Del(key)
+		return nil
+	})
+	if err != nil {
+		log.Printf("unable to get dependent triggers: %v", err)
+		return
+	}
+	ids := sMembers.Val()
+
+	for _, dep := range ids {
+		keys := []string{c.dependenciesKey(dep), c.waitingKey()}
+		var member string
+		member, err = releaseDependentScript.Run(c.c, keys, id, dep).String()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			log.Printf("unable to release dependent trigger: %v", err)
+			continue
+		}
+
+		var t *Trigger
+		t, err = c.decode(member)
+		if err != nil {
+			log.Printf("unable to decode dependent trigger: %v", err)
+			continue
+		}
+		if _, err = c.insert(t, []byte(member)); err != nil {
+			log.Printf("unable to schedule dependent trigger: %v", err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package rc
+
+import (
+	"testing"
+	"time"
+)
+
+// isWaiting returns true if trigger is held by its dependencies
+func isWaiting(t *testing.T, c *Client, id string) bool {
+	t.Helper()
+	ok, err := c.c.HExists(c.waitingKey(), id).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ok
+}
+
+func TestDependentAddedWhileDependencyIsFired(t *testing.T) {
+	c, _ := newTestClient(t)
+	now := time.Now()
+	c.RegisterTriggerHandler("a", func(*Trigger) error {
+		return c.AddTrigger(&Trigger{ID: "b", Handler: "b", DateTime: now, DependsOn: []string{"a"}})
+	})
+	if err := c.AddTrigger(&Trigger{ID: "a", Handler: "a", DateTime: now.Add(-time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.ProcessOnce(); err != nil {
+		t.Fatal(err)
+	}
+	if isWaiting(t, c, "b") {
+		t.Fatal("expected dependent trigger to be released")
+	}
+	if _, err := c.Get("b"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDependentsReleasedWithoutFiring(t *testing.T) {
+	now := time.Now().Add(-time.Second)
+	for name, complete := range map[string]func(c *Client) error{
+		"cancel": func(c *Client) error {
+			return c.Cancel("a")
+		},
+		"pop": func(c *Client) error {
+			_, err := c.PopReady(1)
+			return err
+		},
+		"ack": func(c *Client) error {
+			if _, err := c.Claim("w", 1, time.Minute); err != nil {
+				return err
+			}
+			_, err := c.Ack("w", []string{"a"})
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, _ := newTestClient(t)
+			if err := c.AddTrigger(&Trigger{ID: "a", Handler: "h", DateTime: now}); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.AddTrigger(&Trigger{ID: "b", Handler: "h", DateTime: now.Add(time.Hour), DependsOn: []string{"a"}}); err != nil {
+				t.Fatal(err)
+			}
+			if !isWaiting(t, c, "b") {
+				t.Fatal("expected dependent trigger to be waiting")
+			}
+
+			if err := complete(c); err != nil {
+				t.Fatal(err)
+			}
+			if isWaiting(t, c, "b") {
+				t.Fatal("expected dependent trigger to be released")
+			}
+			if _, err := c.Get("b"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestAddTriggerWithResultDependsOn(t *testing.T) {
+	c, _ := newTestClient(t)
+	now := time.Now().Add(-time.Second)
+	c.RegisterHandler("h", func() {})
+	if err := c.AddTrigger(&Trigger{ID: "a", Handler: "h", DateTime: now.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.AddTriggerWithResult(&Trigger{ID: "b", Handler: "h", DateTime: now, DependsOn: []string{"a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isWaiting(t, c, "b") {
+		t.Fatal("expected dependent trigger to be waiting")
+	}
+
+	if err = c.Cancel("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.ProcessOnce(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err = <-res:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected result of the released trigger")
+	}
+}
@@ -0,0 +1,51 @@
+// Package rc provides cron over Redis: triggers are stored in Redis
+// and fired by the registered handlers of clients.
+//
+// # Wire format
+//
+// Format of triggers is stable and can be used by clients
+// in other languages to schedule triggers.
+//
+// Trigger is stored as a JSON object in the Redis SET of its key.
+// The default key is <pattern>-<scheduled time in unix seconds>, e.g.
+// rc-1700000000, where pattern is Pattern of ClientOptions ("rc" by
+// default, "{rc}" with HashTag). To schedule the trigger other client
+// should SADD it to the key and HSET <pattern>:ids <id> <key>, the index
+// is used by Get, Cancel and dependencies. In the Adaptive mode it should
+// PUBLISH the scheduled time in unix nanoseconds to <pattern>:notify too.
+//
+// Fields of the JSON object:
+//
+//	id               string, required, unique ID of the trigger
+//	date_time        time, required, scheduled time
+//	handler          string, name of the registered handler
+//	namespace        string
+//	payload          any JSON value which is passed to the handler
+//	labels           object of strings
+//	meta             object of strings
+//	dedup_key        string
+//	coalesce_key     string
+//	next             string, name of the registered next function
+//	cron_spec        string, 5-field cron spec of the recurring trigger
+//	cron_location    string, IANA location in which cron_spec is evaluated
+//	overlap          string, "queue", "skip" or "concurrent"
+//	attempt          integer, 0 for the new trigger
+//	max_attempts     integer
+//	next_attempt     time
+//	earliest_start   time
+//	deadline         time
+//	expire_at        time
+//	max_staleness    integer, nanoseconds
+//	depends_on       array of IDs
+//	source           string
+//
+// Unknown fields are ignored. Time is written by this package in
+// RFC 3339 with nanoseconds, e.g. "2023-11-14T22:13:20.5Z". On reading
+// RFC 3339 without the time zone, e.g. "2023-11-14T22:13:20", or with
+// a space instead of "T", as written by Python datetime, is accepted too
+// and treated as UTC; null, "" and "0001-01-01T00:00:00Z" are zero time,
+// numbers are unix seconds. Scheduled time of the trigger is the latest
+// of date_time, earliest_start and next_attempt, the key should be of it.
+// With EncryptionKeys payloads are encrypted and can't be written
+// by other clients
+package rc
@@ -0,0 +1,55 @@
+package rc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// durableKey returns key which is written before WAIT
+// by AddTriggerDurable, see AddTriggerDurable
+func (c *Client) durableKey() string {
+	return fmt.Sprintf("%s:durable", c.prefix)
+}
+
+// AddTriggerDurable provides adding of the new trigger as AddTrigger
+// and waiting until the write is acknowledged by at least
+// replicas replicas with the WAIT command. It returns an error
+// if the trigger is not replicated before the timeout,
+// in this case the trigger is still stored on the master.
+// Each call is blocked up to the timeout, so it should be used
+// only for triggers which must not be lost on failover
+func (c *Client) AddTriggerDurable(t *Trigger, replicas int, timeout time.Duration) error {
+
+	encodedT, err := c.encodeTrigger(t)
+	if err != nil {
+		return err
+	}
+	if _, err = c.add(t, encodedT); err != nil {
+		return err
+	}
+
+	// WAIT waits for writes of its connection, so ID of the trigger is
+	// written by the same connection. Writes are replicated in order,
+	// so the trigger is replicated before it
+	var wait *redis.Cmd
+	_, err = c.c.Pipelined(func(pipe redis.Pipeliner) error {
+		pipe.Set(c.durableKey(), t.ID, 0)
+		wait = pipe.Do("wait", replicas, int64(timeout/time.Millisecond))
+		return nil
+	})
+	if err != nil && err != wait.Err() {
+		return c.closedOr(fmt.Errorf("unable to write trigger: %v", err))
+	}
+
+	acked, err := wait.Int64()
+	if err != nil {
+		return fmt.Errorf("unable to wait for replicas: %v", err)
+	}
+	if int(acked) < replicas {
+		return fmt.Errorf("trigger is replicated to %d of %d replicas", acked, replicas)
+	}
+
+	return nil
+}
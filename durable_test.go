@@ -0,0 +1,19 @@
+package rc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddTriggerDurableCallsOnAdd(t *testing.T) {
+	var added []string
+	c, _ := newTestClient(t, func(o *ClientOptions) {
+		o.OnAdd = func(t *Trigger) { added = append(added, t.ID) }
+	})
+
+	// WAIT is not supported by miniredis, so only adding is checked
+	_ = c.AddTriggerDurable(&Trigger{ID: "a", Handler: "h", DateTime: time.Now()}, 0, time.Millisecond)
+	if len(added) != 1 || added[0] != "a" {
+		t.Fatalf("expected OnAdd to be called with the trigger, got %v", added)
+	}
+}
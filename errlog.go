@@ -0,0 +1,44 @@
+package rc
+
+import (
+	"log"
+	"time"
+)
+
+// defaultErrorLogWindow is a default window of
+// coalescing of identical errors of the poll loop
+const defaultErrorLogWindow = time.Minute
+
+// errorLog provides logging of errors of the poll loop where
+// identical consecutive errors are collapsed into a single line
+// with a count. Its used only by the Start goroutine
+type errorLog struct {
+	window   time.Duration
+	last     string
+	loggedAt time.Time
+	repeated int
+}
+
+// log provides logging of the error if it differs from the
+// previous one or the coalesce window is passed
+func (l *errorLog) log(err error) {
+	msg := err.Error()
+	if msg == l.last && time.Since(l.loggedAt) < l.window {
+		l.repeated++
+		return
+	}
+
+	l.flush()
+	log.Printf("unable to get ready triggers: %v", err)
+	l.last = msg
+	l.loggedAt = time.Now()
+}
+
+// flush provides logging of the count of collapsed errors
+func (l *errorLog) flush() {
+	if l.repeated > 0 {
+		log.Printf("last error repeated %d times", l.repeated)
+	}
+	l.repeated = 0
+	l.last = ""
+}
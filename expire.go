@@ -0,0 +1,76 @@
+package rc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis"
+)
+
+// expiringSeparator separates the key of the trigger
+// and ID of the expiring trigger
+const expiringSeparator = ":expiring:"
+
+// indexedScript returns 1 if the trigger is in the index of IDs and
+// its key exists. Entry of the key which is removed by Redis on ExpireAt
+// is removed from the index and from the FIFO set of the namespace
+var indexedScript = redis.NewScript(`
+local key = redis.call("HGET", KEYS[1], ARGV[1])
+if not key then
+	return 0
+end
+if redis.call("EXISTS", key) == 1 then
+	return 1
+end
+redis.call("HDEL", KEYS[1], ARGV[1])
+if KEYS[2] then
+	redis.call("ZREM", KEYS[2], ARGV[1])
+end
+return 0
+`)
+
+// isIndexed returns true if the trigger is scheduled. Triggers which
+// keys are removed by Redis on ExpireAt are removed from the index
+func (c *Client) isIndexed(id string, keys ...string) (bool, error) {
+	n, err := indexedScript.Run(c.c, append([]string{c.indexKey}, keys...), id).Int64()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// expiringKey returns the own key of the expiring trigger. Its a SET
+// with the only member, so the trigger is processed as triggers
+// of another keys and removed by Redis with the key at ExpireAt
+func (c *Client) expiringKey(t *Trigger) string {
+	return fmt.Sprintf("%s%s%s", c.keyFunc(t), expiringSeparator, t.ID)
+}
+
+// expire provides setting of the expiry
+// of the key of the expiring trigger
+func (c *Client) expire(pipe redis.Pipeliner, key string, t *Trigger) {
+	if !t.ExpireAt.IsZero() {
+		pipe.PExpireAt(key, t.ExpireAt)
+	}
+}
+
+// expireAtArg returns ExpireAt of the trigger in milliseconds
+// for scripts. Its 0 for triggers without expiry
+func expireAtArg(t *Trigger) string {
+	if t.ExpireAt.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(toMillis(t.ExpireAt), base10)
+}
+
+// baseKey returns the key made by KeyFunc
+// of the overflow or expiring key
+func baseKey(key string) string {
+	for _, sep := range []string{expiringSeparator, overflowSeparator} {
+		if i := strings.LastIndex(key, sep); i >= 0 {
+			return key[:i]
+		}
+	}
+	return key
+}
@@ -0,0 +1,53 @@
+package rc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpiredTriggerDoesNotBlockFIFONamespace(t *testing.T) {
+	c, m := newTestClient(t, func(o *ClientOptions) {
+		o.FIFONamespaces = []string{"q"}
+	})
+	var fired []string
+	c.RegisterTriggerHandler("h", func(t *Trigger) error {
+		fired = append(fired, t.ID)
+		return nil
+	})
+
+	now := time.Now()
+	if err := c.AddTrigger(&Trigger{ID: "a", Handler: "h", Namespace: "q", DateTime: now.Add(time.Hour), ExpireAt: now.Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddTrigger(&Trigger{ID: "b", Handler: "h", Namespace: "q", DateTime: now.Add(-time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+	m.FastForward(2 * time.Minute)
+
+	if _, err := c.ProcessOnce(); err != nil {
+		t.Fatal(err)
+	}
+	if len(fired) != 1 || fired[0] != "b" {
+		t.Fatalf("expected trigger after the expired one to be fired, got %v", fired)
+	}
+	if m.HGet(c.indexKey, "a") != "" {
+		t.Fatal("expected expired trigger to be removed from the index")
+	}
+}
+
+func TestExpiredDependencyIsCompleted(t *testing.T) {
+	c, m := newTestClient(t)
+
+	now := time.Now()
+	if err := c.AddTrigger(&Trigger{ID: "a", Handler: "h", DateTime: now.Add(time.Hour), ExpireAt: now.Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	m.FastForward(2 * time.Minute)
+
+	if err := c.AddTrigger(&Trigger{ID: "b", Handler: "h", DateTime: now, DependsOn: []string{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get("b"); err != nil {
+		t.Fatalf("expected trigger to be scheduled since its dependency is expired: %v", err)
+	}
+}
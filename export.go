@@ -0,0 +1,159 @@
+package rc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Export provides writing of all pending triggers to w.
+// Triggers are written as JSON lines, one trigger per line,
+// in the same form as they are stored in Redis. Triggers which are
+// waiting for their dependencies or held by Debounce are written too.
+// Triggers of each key are written to w as they are read
+func (c *Client) Export(w io.Writer) error {
+
+	keys, err := c.getKeys()
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, k := range keys {
+		sCmd := c.c.SMembers(k)
+		if sCmd.Err() != nil {
+			return fmt.Errorf("unable to get triggers by the key %s: %v", k, sCmd.Err())
+		}
+		if err = writeMembers(bw, sCmd.Val()); err != nil {
+			return err
+		}
+	}
+	debounceKey, _ := c.debounceKeys()
+	for _, k := range []string{c.waitingKey(), debounceKey} {
+		hCmd := c.c.HVals(k)
+		if hCmd.Err() != nil {
+			return fmt.Errorf("unable to get triggers by the key %s: %v", k, hCmd.Err())
+		}
+		if err = writeMembers(bw, hCmd.Val()); err != nil {
+			return err
+		}
+	}
+
+	if err = bw.Flush(); err != nil {
+		return fmt.Errorf("unable to write trigger: %v", err)
+	}
+	return nil
+}
+
+// writeMembers provides writing of encoded triggers as JSON lines
+func writeMembers(bw *bufio.Writer, members []string) error {
+	for _, m := range members {
+		if _, err := bw.WriteString(m + "\n"); err != nil {
+			return fmt.Errorf("unable to write trigger: %v", err)
+		}
+	}
+	return nil
+}
+
+// Import provides reading of triggers from r which was written
+// by Export and storing of them as they were exported, with the same
+// IDs and scheduled times. Its not checked by RejectPast, quotas
+// and MaxPending and ScheduleJitter is not applied. Triggers which
+// IDs are already pending are skipped, so the same export can be
+// imported again
+func (c *Client) Import(r io.Reader) error {
+	_, err := c.ImportCount(r)
+	return err
+}
+
+// ImportCount provides importing of triggers as Import
+// and returns number of imported triggers
+func (c *Client) ImportCount(r io.Reader) (int, error) {
+
+	if c.isClosed() {
+		return 0, ErrClosed
+	}
+
+	var (
+		ts      []*Trigger
+		members = map[*Trigger][]byte{}
+		ids     = map[string]struct{}{}
+	)
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return 0, fmt.Errorf("unable to read trigger: %v", err)
+		}
+		if s := strings.TrimSpace(line); s != "" {
+			t, dErr := c.decode(s)
+			if dErr != nil {
+				return 0, fmt.Errorf("unable to decode trigger: %v", dErr)
+			}
+			if t.ID == "" {
+				return 0, fmt.Errorf("trigger %s has no ID", s)
+			}
+			ts = append(ts, t)
+			members[t] = []byte(s)
+			ids[t.ID] = struct{}{}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+
+	var imported int
+	for len(ts) > 0 {
+		// dependencies are imported before their dependents,
+		// so dependents are held until the dependencies are fired
+		var rest []*Trigger
+		for _, t := range ts {
+			if !importedDependencies(t, ids) {
+				rest = append(rest, t)
+				continue
+			}
+			ok, err := c.importTrigger(t, members[t])
+			if err != nil {
+				return imported, err
+			}
+			if ok {
+				imported++
+			}
+			delete(ids, t.ID)
+		}
+		if len(rest) == len(ts) {
+			return imported, ErrDependencyCycle
+		}
+		ts = rest
+	}
+
+	return imported, nil
+}
+
+// importTrigger provides storing of the imported trigger
+// if its ID is not pending. It returns true if its stored
+func (c *Client) importTrigger(t *Trigger, encodedT []byte) (bool, error) {
+	ok, err := c.isPending(t.ID)
+	if err != nil {
+		return false, c.closedOr(fmt.Errorf("unable to check trigger %s: %v", t.ID, err))
+	}
+	if ok {
+		return false, nil
+	}
+	if _, err = c.add(t, encodedT); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// importedDependencies returns true if no dependency
+// of the trigger is left to be imported
+func importedDependencies(t *Trigger, ids map[string]struct{}) bool {
+	for _, id := range t.DependsOn {
+		if _, ok := ids[id]; ok && id != t.ID {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package rc
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestExportImport(t *testing.T) {
+	src, _ := newTestClient(t)
+	at := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := src.AddTrigger(&Trigger{ID: "a", Handler: "h", DateTime: at}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.AddTrigger(&Trigger{ID: "b", Handler: "h", DateTime: at, DependsOn: []string{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := src.Export(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	dst, _ := newTestClient(t, func(o *ClientOptions) {
+		o.RejectPast = true
+		o.ScheduleJitter = time.Hour
+	})
+	n, err := dst.ImportCount(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 imported triggers, got %d", n)
+	}
+	a, err := dst.Get("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.DateTime.Equal(at) {
+		t.Fatalf("expected time %v, got %v", at, a.DateTime)
+	}
+	if ok, _ := dst.c.HExists(dst.waitingKey(), "b").Result(); !ok {
+		t.Fatal("expected dependent trigger to be waiting")
+	}
+
+	n, err = dst.ImportCount(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("expected pending triggers to be skipped, got %d imported", n)
+	}
+	if err = dst.Import(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+}
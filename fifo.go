@@ -0,0 +1,88 @@
+package rc
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis"
+)
+
+// fifoKey returns key of the sorted set with IDs of pending
+// triggers of the FIFO namespace by their Seq
+func (c *Client) fifoKey(namespace string) string {
+	return fmt.Sprintf("%s:fifo:%s", c.prefix, namespace)
+}
+
+// fifoSeqKey returns key of the counter
+// of Seq of the FIFO namespace
+func (c *Client) fifoSeqKey(namespace string) string {
+	return fmt.Sprintf("%s:fifo:%s:seq", c.prefix, namespace)
+}
+
+// isFIFO returns true if triggers of the namespace are fired in order
+func (c *Client) isFIFO(namespace string) bool {
+	_, ok := c.fifoNamespaces[namespace]
+	return ok
+}
+
+// sequence provides setting of Seq of the new trigger of the FIFO
+// namespace. Recurring, debounced and dependent triggers are not sequenced
+func (c *Client) sequence(t *Trigger) error {
+	if t.Seq != 0 || !c.isFIFO(t.Namespace) || t.Next != "" || t.CronSpec != "" ||
+		len(t.DependsOn) > 0 || (c.debounce > 0 && t.DedupKey != "") {
+		return nil
+	}
+
+	seq, err := c.c.Incr(c.fifoSeqKey(t.Namespace)).Result()
+	if err != nil {
+		return c.closedOr(fmt.Errorf("unable to get sequence of the trigger: %v", err))
+	}
+	t.Seq = seq
+	return nil
+}
+
+// addSequenced provides adding of the sequenced trigger
+// to the FIFO set of its namespace
+func (c *Client) addSequenced(pipe redis.Pipeliner, t *Trigger) {
+	if t.Seq == 0 {
+		return
+	}
+	pipe.ZAdd(c.fifoKey(t.Namespace), redis.Z{
+		Score:  float64(t.Seq),
+		Member: t.ID,
+	})
+}
+
+// fifoReady returns true if the trigger is the first pending
+// trigger of its FIFO namespace. Triggers which are not pending
+// anymore, including expired by ExpireAt, are removed from the head
+// of the FIFO set. Only one
+// trigger of the namespace is fired by the check, since the next
+// one can be fired only after the previous one is removed
+func (c *Client) fifoReady(t *Trigger, r *cycle) bool {
+	if t.Seq == 0 || !c.isFIFO(t.Namespace) {
+		return true
+	}
+	if _, ok := r.fifoFired[t.Namespace]; ok {
+		return false
+	}
+
+	key := c.fifoKey(t.Namespace)
+	for {
+		head, err := c.c.ZRange(key, 0, 0).Result()
+		if err != nil {
+			return false
+		}
+		if len(head) == 0 || head[0] == t.ID {
+			r.fifoFired[t.Namespace] = struct{}{}
+			return true
+		}
+
+		ok, err := c.isIndexed(head[0], key)
+		if err != nil || ok {
+			return false
+		}
+		if err = c.c.ZRem(key, head[0]).Err(); err != nil {
+			return false
+		}
+	}
+}
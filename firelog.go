@@ -0,0 +1,59 @@
+package rc
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// FiredLog defines a structured record which is logged
+// for each fired trigger with LogFired option. Its fields are
+// stable and can be used for analytics of logs
+type FiredLog struct {
+	ID        string `json:"id"`
+	Namespace string `json:"namespace"`
+	Handler   string `json:"handler"`
+	Source    string `json:"source,omitempty"`
+	// Meta is Meta of the trigger
+	Meta map[string]string `json:"meta,omitempty"`
+	// ScheduledTime is a time on which trigger was scheduled
+	ScheduledTime time.Time `json:"scheduled_time"`
+	// FiredTime is a time on which handler was called
+	FiredTime time.Time `json:"fired_time"`
+	// DriftMs is a delay of firing after ScheduledTime in milliseconds
+	DriftMs int64 `json:"drift_ms"`
+	Success bool  `json:"success"`
+	// Error is an error of the handler
+	Error string `json:"error,omitempty"`
+}
+
+// logFired provides logging of the fired trigger
+// as JSON, if LogFired option is set
+func (c *Client) logFired(t *Trigger, firedAt time.Time, err error) {
+	if !c.logFiredTriggers {
+		return
+	}
+
+	scheduled := t.scheduledTime()
+	r := &FiredLog{
+		ID:            t.ID,
+		Namespace:     t.Namespace,
+		Handler:       t.Handler,
+		Source:        t.Source,
+		Meta:          t.Meta,
+		ScheduledTime: scheduled.UTC(),
+		FiredTime:     firedAt.UTC(),
+		DriftMs:       int64(firedAt.Sub(scheduled) / time.Millisecond),
+		Success:       err == nil,
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	b, mErr := json.Marshal(r)
+	if mErr != nil {
+		log.Printf("unable to marshal fired trigger log: %v", mErr)
+		return
+	}
+	log.Print(string(b))
+}
@@ -0,0 +1,79 @@
+package rc
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// ErrNotFound is returned when trigger is not found
+var ErrNotFound = errors.New("trigger is not found")
+
+// ErrWriteLost is returned with VerifyWrites option
+// when trigger is not found after its write
+var ErrWriteLost = errors.New("trigger is not found after write")
+
+// ErrPast is returned with RejectPast option
+// when trigger is scheduled in the past
+var ErrPast = errors.New("trigger is scheduled in the past")
+
+// ErrBacklogFull is returned with MaxPending option
+// when number of pending triggers exceeds it
+var ErrBacklogFull = errors.New("backlog of triggers is full")
+
+// ErrQuotaExceeded is returned with NamespaceQuotas option
+// when namespace of the trigger has its quota of pending triggers
+var ErrQuotaExceeded = errors.New("quota of the namespace is exceeded")
+
+// Get returns trigger by the ID. It returns ErrNotFound
+// if trigger is fired, removed or never existed
+func (c *Client) Get(id string) (*Trigger, error) {
+
+	key, err := c.c.HGet(c.indexKey, id).Result()
+	if err == redis.Nil {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get key of the trigger: %v", err)
+	}
+
+	ts, err := c.getTriggers(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get triggers: %v", err)
+	}
+	for _, t := range ts {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// TimeUntil returns duration until the trigger is fired by its
+// scheduled time, its negative if trigger is overdue. Recurring trigger
+// is stored with its next occurrence, so its duration until it.
+// It returns ErrNotFound if trigger is not pending
+func (c *Client) TimeUntil(id string) (time.Duration, error) {
+
+	t, err := c.Get(id)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.scheduledTime().Sub(c.now()), nil
+}
+
+// newID returns a random UUID (version 4)
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Errorf("unable to generate id: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
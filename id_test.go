@@ -0,0 +1,38 @@
+package rc
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIDGenerator(t *testing.T) {
+	n := 0
+	c, _ := newTestClient(t, func(o *ClientOptions) {
+		o.IDGenerator = func() string {
+			n++
+			return fmt.Sprintf("id-%d", n)
+		}
+	})
+
+	for i := 1; i <= 2; i++ {
+		tr := &Trigger{Handler: "h", DateTime: time.Now().Add(time.Hour)}
+		if err := c.AddTrigger(tr); err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("id-%d", i); tr.ID != want {
+			t.Fatalf("expected ID %s, got %s", want, tr.ID)
+		}
+		if _, err := c.Get(tr.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tr := &Trigger{ID: "own", Handler: "h", DateTime: time.Now().Add(time.Hour)}
+	if err := c.AddTrigger(tr); err != nil {
+		t.Fatal(err)
+	}
+	if tr.ID != "own" || n != 2 {
+		t.Fatalf("expected ID of the trigger to be kept, got %s", tr.ID)
+	}
+}
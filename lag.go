@@ -0,0 +1,59 @@
+package rc
+
+import (
+	"log"
+	"time"
+)
+
+// checkLag provides calling of OnLag when number of overdue
+// triggers of keys stays above the threshold longer than the lag duration
+func (c *Client) checkLag(keys []string) {
+	if c.onLag == nil {
+		return
+	}
+
+	overdue, err := c.countOverdueOf(keys)
+	if err != nil {
+		log.Printf("unable to count overdue triggers: %v", err)
+		return
+	}
+
+	if overdue <= c.lagThreshold {
+		c.lagSince = time.Time{}
+		c.lagReported = false
+		return
+	}
+
+	now := c.now()
+	if c.lagSince.IsZero() {
+		c.lagSince = now
+	}
+	if !c.lagReported && now.Sub(c.lagSince) >= c.lagDuration {
+		c.lagReported = true
+		c.onLag(overdue)
+	}
+}
+
+// countOverdue returns number of triggers which are
+// stored in ready keys
+func (c *Client) countOverdue() (int64, error) {
+
+	keys, err := c.getKeys()
+	if err != nil {
+		return 0, err
+	}
+
+	return c.countOverdueOf(keys)
+}
+
+// countOverdueOf returns number of triggers which are
+// stored in ready keys of keys
+func (c *Client) countOverdueOf(keys []string) (int64, error) {
+
+	readyKeys, err := c.filterTimestamps(keys)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.countTriggers(readyKeys)
+}
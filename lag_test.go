@@ -0,0 +1,45 @@
+package rc
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLagDurationByClock(t *testing.T) {
+	clock := &testClock{t: time.Now().Truncate(time.Second)}
+	var overdue int64
+	c, _ := newTestClient(t, func(o *ClientOptions) {
+		o.Clock = clock.now
+		o.OnLag = func(n int64) { overdue = n }
+		o.LagDuration = time.Minute
+	})
+	if err := c.AddTrigger(&Trigger{ID: "a", Handler: "h", DateTime: clock.now().Add(-time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := c.getKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.checkLag(keys)
+	if overdue != 0 {
+		t.Fatalf("expected OnLag not called before LagDuration, got %d", overdue)
+	}
+	clock.add(time.Minute)
+	c.checkLag(keys)
+	if overdue != 1 {
+		t.Fatalf("expected OnLag after LagDuration of the clock, got %d", overdue)
+	}
+}
+
+func TestLastErrorTimeByClock(t *testing.T) {
+	clock := &testClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c, _ := newTestClient(t, func(o *ClientOptions) {
+		o.Clock = clock.now
+	})
+	c.setLastError(errors.New("x"))
+	if _, at := c.LastError(); !at.Equal(clock.now()) {
+		t.Fatalf("expected time of the error by the clock, got %v", at)
+	}
+}
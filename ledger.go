@@ -0,0 +1,58 @@
+package rc
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// ledgerScript provides adding of the token of the firing to the
+// ledger if its not there within the window. Tokens which are older
+// than the window are removed, the ledger itself is removed by Redis
+// if nothing is fired within the window
+var ledgerScript = redis.NewScript(`
+local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if score and tonumber(score) > tonumber(ARGV[3]) then
+	return 0
+end
+redis.call("ZADD", KEYS[1], ARGV[2], ARGV[1])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", "(" .. ARGV[3])
+redis.call("PEXPIRE", KEYS[1], ARGV[4])
+return 1
+`)
+
+// ledgerKey returns key of the sorted set with the fired triggers
+func (c *Client) ledgerKey() string {
+	return fmt.Sprintf("%s:ledger", c.prefix)
+}
+
+// firingToken returns token of the firing of the trigger,
+// so retries and next occurrences of recurring triggers
+// are not considered as duplicates
+func firingToken(t *Trigger) string {
+	return t.ID + ":" + strconv.Itoa(t.Attempt) + ":" + strconv.FormatInt(t.scheduledTime().UnixNano(), base10)
+}
+
+// recordFiring returns false if the trigger is already
+// fired within IdempotencyWindow. Otherwise its recorded
+// in the ledger. Its true if the ledger is not available
+func (c *Client) recordFiring(t *Trigger) bool {
+	if c.idempotencyWindow <= 0 {
+		return true
+	}
+
+	now := toMillis(c.now())
+	window := int64(c.idempotencyWindow / time.Millisecond)
+	n, err := ledgerScript.Run(c.c, []string{c.ledgerKey()}, firingToken(t),
+		strconv.FormatInt(now, base10), strconv.FormatInt(now-window, base10),
+		strconv.FormatInt(window, base10)).Int64()
+	if err != nil {
+		log.Printf("unable to record firing of the trigger: %v", err)
+		return true
+	}
+
+	return n == 1
+}
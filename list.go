@@ -0,0 +1,243 @@
+package rc
+
+import (
+	"fmt"
+	"time"
+)
+
+// ListOverdue returns triggers which scheduled time is passed
+// but which are still not fired and present in Redis
+func (c *Client) ListOverdue() (Triggers, error) {
+
+	readyKeys, err := c.getReadyKeys()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get ready keys: %v", err)
+	}
+
+	now := c.now()
+	var r Triggers
+	for _, k := range readyKeys {
+		var ts Triggers
+		ts, err = c.getTriggers(k)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get triggers: %v", err)
+		}
+		for _, t := range ts {
+			if !t.scheduledTime().After(now) {
+				r = append(r, t)
+			}
+		}
+	}
+	r.sort()
+
+	return r, nil
+}
+
+// ListDueWithin returns pending triggers which scheduled time is
+// between now and now+d, sorted by the scheduled time. Only keys
+// which time is not before the current second are read, so with
+// KeyFunc time of the key should be the scheduled time of its triggers
+func (c *Client) ListDueWithin(d time.Duration) (Triggers, error) {
+
+	keys, err := c.getKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	from, to := now.Truncate(time.Second), now.Add(d)
+	var r Triggers
+	for _, k := range keys {
+		kt, ok := c.keyTime(k)
+		if !ok || kt.Before(from) || kt.After(to) {
+			continue
+		}
+
+		var ts Triggers
+		ts, err = c.getTriggers(k)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get triggers: %v", err)
+		}
+		for _, t := range ts {
+			st := t.scheduledTime()
+			if !st.Before(now) && !st.After(to) {
+				r = append(r, t)
+			}
+		}
+	}
+	r.sort()
+
+	return r, nil
+}
+
+// List returns up to limit triggers starting from the cursor and
+// a cursor of the next page. Empty cursor means the first page,
+// empty next cursor means that there are no more triggers.
+// Keys are iterated with SCAN, so ordering across pages is
+// best-effort: triggers which are added or removed while paging
+// may be missed or returned twice. Triggers of the one key are
+// ordered by the scheduled time. Keys which can't be parsed are skipped
+func (c *Client) List(cursor string, limit int) (Triggers, string, error) {
+
+	if limit <= 0 {
+		return nil, "", fmt.Errorf("limit should be positive")
+	}
+
+	scanCursor, keyIndex, offset, err := parseListCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var (
+		r    Triggers
+		ts   Triggers
+		keys []string
+		next uint64
+	)
+	for {
+		keys, next, err = c.c.Scan(scanCursor, c.keyMatch, int64(limit)).Result()
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to scan keys: %v", err)
+		}
+		keys = c.triggerKeys(keys)
+
+		for ; keyIndex < len(keys); keyIndex++ {
+			if len(r) == limit {
+				return r, formatListCursor(scanCursor, keyIndex, 0), nil
+			}
+			ts, err = c.getTriggers(keys[keyIndex])
+			if err != nil {
+				return nil, "", fmt.Errorf("unable to get triggers: %v", err)
+			}
+			ts.sort()
+			for ; offset < len(ts); offset++ {
+				if len(r) == limit {
+					return r, formatListCursor(scanCursor, keyIndex, offset), nil
+				}
+				r = append(r, ts[offset])
+			}
+			offset = 0
+		}
+
+		if next == 0 {
+			return r, "", nil
+		}
+		scanCursor, keyIndex = next, 0
+		if len(r) == limit {
+			return r, formatListCursor(scanCursor, 0, 0), nil
+		}
+	}
+}
+
+// formatListCursor returns cursor of List which points to the
+// offset of the trigger in the key with keyIndex within the keys
+// returned by SCAN from scanCursor
+func formatListCursor(scanCursor uint64, keyIndex, offset int) string {
+	return fmt.Sprintf("%d-%d-%d", scanCursor, keyIndex, offset)
+}
+
+func parseListCursor(cursor string) (uint64, int, int, error) {
+	if cursor == "" {
+		return 0, 0, 0, nil
+	}
+
+	var (
+		scanCursor       uint64
+		keyIndex, offset int
+	)
+	_, err := fmt.Sscanf(cursor, "%d-%d-%d", &scanCursor, &keyIndex, &offset)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	return scanCursor, keyIndex, offset, nil
+}
+
+// NextFireTime returns the earliest scheduled time of pending
+// triggers. It returns false if there are no pending triggers
+func (c *Client) NextFireTime() (time.Time, bool, error) {
+
+	keys, err := c.getKeys()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return c.nextFireTime(keys)
+}
+
+// nextFireTime returns the earliest scheduled time of triggers of keys
+func (c *Client) nextFireTime(keys []string) (time.Time, bool, error) {
+
+	var (
+		minKey  string
+		minTime time.Time
+	)
+	for _, k := range keys {
+		kt, ok := c.keyTime(k)
+		if !ok {
+			continue
+		}
+		if minKey == "" || kt.Before(minTime) {
+			minKey, minTime = k, kt
+		}
+	}
+	if minKey == "" {
+		return time.Time{}, false, nil
+	}
+
+	ts, err := c.getTriggers(minKey)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("unable to get triggers: %v", err)
+	}
+	if len(ts) == 0 {
+		return minTime, true, nil
+	}
+	ts.sort()
+
+	return ts[0].scheduledTime(), true, nil
+}
+
+// ListByLabel returns pending triggers which have
+// the label key with the value
+func (c *Client) ListByLabel(key, value string) (Triggers, error) {
+	return c.listWhere(func(t *Trigger) bool {
+		v, ok := t.Labels[key]
+		return ok && v == value
+	})
+}
+
+// ListByMeta returns pending triggers which have
+// the key of Meta with the value
+func (c *Client) ListByMeta(key, value string) (Triggers, error) {
+	return c.listWhere(func(t *Trigger) bool {
+		v, ok := t.Meta[key]
+		return ok && v == value
+	})
+}
+
+// listWhere returns pending triggers of all keys
+// for which fn returns true, sorted by the scheduled time
+func (c *Client) listWhere(fn func(t *Trigger) bool) (Triggers, error) {
+
+	keys, err := c.getKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	var r Triggers
+	for _, k := range keys {
+		var ts Triggers
+		ts, err = c.getTriggers(k)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get triggers: %v", err)
+		}
+		for _, t := range ts {
+			if fn(t) {
+				r = append(r, t)
+			}
+		}
+	}
+	r.sort()
+
+	return r, nil
+}
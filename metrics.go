@@ -0,0 +1,58 @@
+package rc
+
+import "time"
+
+// Names of metrics which are reported to MetricsSink
+const (
+	// MetricTriggersAdded is a counter of triggers added by
+	// the client. Labels: namespace
+	MetricTriggersAdded = "rc_triggers_added_total"
+	// MetricTriggersRemoved is a counter of triggers removed by
+	// RemoveTrigger, Cancel or CancelMany. Labels: namespace
+	MetricTriggersRemoved = "rc_triggers_removed_total"
+	// MetricTriggersFired is a counter of fired triggers.
+	// Labels: namespace, handler, status ("ok" or "failed")
+	MetricTriggersFired = "rc_triggers_fired_total"
+	// MetricHandlerDuration is a duration of the handler.
+	// Labels: namespace, handler, status ("ok" or "failed")
+	MetricHandlerDuration = "rc_handler_duration"
+	// MetricTriggersRetried is a counter of failed triggers which
+	// are scheduled for the retry. Labels: namespace, handler
+	MetricTriggersRetried = "rc_triggers_retried_total"
+	// MetricTriggersDead is a counter of triggers moved to
+	// the dead-letter set. Labels: namespace, reason
+	MetricTriggersDead = "rc_triggers_dead_total"
+	// MetricTriggersSkipped is a counter of ready triggers which
+	// are not fired. Labels: namespace, reason (ReasonExpired or ReasonStale)
+	MetricTriggersSkipped = "rc_triggers_skipped_total"
+	// MetricCycleDuration is a duration of the check of
+	// ready triggers of Start. No labels
+	MetricCycleDuration = "rc_cycle_duration"
+	// MetricCycleErrors is a counter of failed checks
+	// of ready triggers of Start. No labels
+	MetricCycleErrors = "rc_cycle_errors_total"
+)
+
+// MetricsSink defines a backend of metrics of the client,
+// e.g. adapter to Prometheus or StatsD. Its methods are called
+// synchronously, so they should not block. Labels should not be
+// changed or kept after the call
+type MetricsSink interface {
+	IncCounter(name string, labels map[string]string)
+	ObserveDuration(name string, d time.Duration, labels map[string]string)
+}
+
+// noopMetrics is a MetricsSink which drops metrics
+type noopMetrics struct{}
+
+func (noopMetrics) IncCounter(string, map[string]string) {}
+
+func (noopMetrics) ObserveDuration(string, time.Duration, map[string]string) {}
+
+// handlerStatus returns status label of the handler result
+func handlerStatus(err error) string {
+	if err != nil {
+		return "failed"
+	}
+	return "ok"
+}
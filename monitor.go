@@ -0,0 +1,42 @@
+package rc
+
+import (
+	"log"
+	"time"
+)
+
+// StartMonitor provides starting of the goroutine which calls fn
+// with numbers of overdue and pending triggers on each interval.
+// Its independent of Start, so it reports growing backlog even
+// if Start is stuck. Monitor is stopped by Close
+func (c *Client) StartMonitor(interval time.Duration, fn func(overdue int64, pending int64)) {
+	c.monitors.Add(1)
+	go func() {
+		defer c.monitors.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				c.monitor(fn)
+			}
+		}
+	}()
+}
+
+func (c *Client) monitor(fn func(overdue int64, pending int64)) {
+	overdue, err := c.countOverdue()
+	if err != nil {
+		log.Printf("unable to count overdue triggers: %v", err)
+		return
+	}
+	pending, err := c.countPending()
+	if err != nil {
+		log.Printf("unable to count pending triggers: %v", err)
+		return
+	}
+	fn(overdue, pending)
+}
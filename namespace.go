@@ -0,0 +1,92 @@
+package rc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ListByNamespace returns pending triggers of the namespace
+// sorted by the scheduled time. With NamespaceKeys only keys
+// of the namespace are read, otherwise all triggers are decoded
+func (c *Client) ListByNamespace(namespace string) (Triggers, error) {
+
+	if !c.namespaceKeys {
+		return c.listWhere(func(t *Trigger) bool {
+			return t.Namespace == namespace
+		})
+	}
+
+	keys, err := c.getNamespaceKeys(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var r Triggers
+	for _, k := range keys {
+		var ts Triggers
+		ts, err = c.getTriggers(k)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get triggers: %v", err)
+		}
+		r = append(r, ts...)
+	}
+	r.sort()
+
+	return r, nil
+}
+
+// CountByNamespace returns number of pending triggers of the
+// namespace. With NamespaceKeys its counted by SCARD of keys of
+// the namespace, otherwise all triggers are decoded
+func (c *Client) CountByNamespace(namespace string) (int64, error) {
+
+	if !c.namespaceKeys {
+		ts, err := c.ListByNamespace(namespace)
+		if err != nil {
+			return 0, err
+		}
+		return int64(len(ts)), nil
+	}
+
+	keys, err := c.getNamespaceKeys(namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.countTriggers(keys)
+}
+
+// getNamespaceKeys returns keys made by namespaceKeyFunc
+// for the namespace
+func (c *Client) getNamespaceKeys(namespace string) ([]string, error) {
+
+	keyPrefix := c.prefix + c.keySeparator + namespace + c.keySeparator
+	cmd := c.c.Keys(globEscape(keyPrefix) + "*")
+	if cmd.Err() != nil {
+		return nil, fmt.Errorf("unable to get keys: %v", cmd.Err())
+	}
+
+	// keys of namespaces which start with the namespace
+	// and the separator are matched too, so they are filtered out
+	var r []string
+	for _, k := range cmd.Val() {
+		if !strings.Contains(strings.TrimPrefix(baseKey(k), keyPrefix), c.keySeparator) {
+			r = append(r, k)
+		}
+	}
+
+	return c.triggerKeys(r), nil
+}
+
+// globEscape returns s with escaped special
+// characters of the glob pattern
+func globEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package rc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+)
+
+func TestDashContainingPrefixes(t *testing.T) {
+	for _, nsKeys := range []bool{false, true} {
+		c, _ := newTestClient(t, func(o *ClientOptions) {
+			o.Pattern = "my-app"
+			o.NamespaceKeys = nsKeys
+		})
+		fired := 0
+		c.RegisterHandler("h", func() { fired++ })
+
+		at := time.Now().Add(-time.Second).Truncate(time.Second)
+		tr := &Trigger{Handler: "h", Namespace: "billing-eu-1", DateTime: at}
+		res, err := c.Schedule(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		kt, err := c.ParseKeyTime(res.Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !kt.Equal(at) {
+			t.Fatalf("expected time %v of the key %s, got %v", at, res.Key, kt)
+		}
+
+		if _, err = c.ProcessOnce(); err != nil {
+			t.Fatal(err)
+		}
+		if fired != 1 {
+			t.Fatalf("expected trigger of the key %s to be fired, got %d", res.Key, fired)
+		}
+	}
+}
+
+func TestKeySeparatorOfNestedPatterns(t *testing.T) {
+	m := miniredis.RunT(t)
+	newPatternClient := func(pattern string) (*Client, *int) {
+		c, err := newClient(&ClientOptions{
+			Options:      redis.Options{Addr: m.Addr()},
+			Pattern:      pattern,
+			KeySeparator: "/",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { c.Close(context.Background()) })
+		fired := new(int)
+		c.RegisterHandler("h", func() { *fired++ })
+		return c, fired
+	}
+	my, myFired := newPatternClient("my")
+	app, appFired := newPatternClient("my-app")
+
+	if err := app.AddTrigger(&Trigger{Handler: "h", DateTime: time.Now().Add(-time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := my.ProcessOnce(); err != nil {
+		t.Fatal(err)
+	}
+	if *myFired != 0 {
+		t.Fatal("expected trigger of the other pattern not to be fired")
+	}
+	if _, err := app.ProcessOnce(); err != nil {
+		t.Fatal(err)
+	}
+	if *appFired != 1 {
+		t.Fatalf("expected trigger to be fired by its pattern, got %d", *appFired)
+	}
+}
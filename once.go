@@ -0,0 +1,37 @@
+package rc
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+func (c *Client) onceKey() string {
+	return fmt.Sprintf("%s:once", c.prefix)
+}
+
+// runStartOnce provides calling of OnStartOnce if this client
+// is the first one which is started since the flag is set
+func (c *Client) runStartOnce() {
+	if c.onStartOnce == nil {
+		return
+	}
+
+	ok, err := c.c.SetNX(c.onceKey(), c.now().UTC().Format(time.RFC3339), c.startOnceWindow).Result()
+	if err != nil {
+		log.Printf("unable to set start once flag: %v", err)
+		return
+	}
+	if ok {
+		c.onStartOnce()
+	}
+}
+
+// ResetStartOnce provides removing of the flag which is set
+// by the client ran OnStartOnce, so it runs again on the next start
+func (c *Client) ResetStartOnce() error {
+	if err := c.c.Del(c.onceKey()).Err(); err != nil {
+		return fmt.Errorf("unable to reset start once flag: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package rc
+
+import (
+	"context"
+	"sync"
+)
+
+// cycle defines a state of the one check of ready triggers
+// which handlers are run by the worker pool
+type cycle struct {
+	ctx     context.Context
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	res     ProcessResult
+	batches []*firedBatch
+	// limited is set when RateLimit is exceeded,
+	// so the rest of triggers is fired on the next check
+	limited bool
+	// fifoFired defines FIFO namespaces which
+	// triggers are already fired by the check
+	fifoFired map[string]struct{}
+}
+
+func (r *cycle) skipped() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.res.Skipped++
+}
+
+func (r *cycle) fired(fired bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if fired {
+		r.res.Fired++
+	}
+	if err != nil {
+		r.res.Failed++
+	}
+}
+
+// PoolStats defines a state of the worker pool
+type PoolStats struct {
+	// Size is a maximum number of concurrently running handlers
+	Size int
+	// Active is a number of running handlers
+	Active int
+	// Idle is a number of free workers
+	Idle int
+	// Queued is a number of triggers which are waiting for the worker
+	Queued int
+}
+
+// SetConcurrency provides resizing of the worker pool. Its safe to
+// call while handlers are running: on shrinking running handlers
+// are not interrupted, new ones are not started until number
+// of running handlers is less than n
+func (c *Client) SetConcurrency(n int) {
+	c.pool.resize(n)
+}
+
+// PoolStats returns a current state of the worker pool
+func (c *Client) PoolStats() PoolStats {
+	return c.pool.stats()
+}
+
+// workerPool defines a limit of concurrently running handlers
+type workerPool struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+	queued int
+}
+
+func newWorkerPool(limit int) *workerPool {
+	if limit <= 0 {
+		limit = 1
+	}
+	p := &workerPool{limit: limit}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// acquire provides waiting for the free worker
+func (p *workerPool) acquire() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queued++
+	for p.active >= p.limit {
+		p.cond.Wait()
+	}
+	p.queued--
+	p.active++
+}
+
+// tryAcquire returns true and takes the worker
+// if its free, without waiting for it
+func (p *workerPool) tryAcquire() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.active >= p.limit {
+		return false
+	}
+	p.active++
+	return true
+}
+
+func (p *workerPool) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active--
+	p.cond.Broadcast()
+}
+
+func (p *workerPool) resize(limit int) {
+	if limit <= 0 {
+		limit = 1
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.limit = limit
+	p.cond.Broadcast()
+}
+
+func (p *workerPool) stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := PoolStats{Size: p.limit, Active: p.active, Queued: p.queued}
+	if p.active < p.limit {
+		s.Idle = p.limit - p.active
+	}
+	return s
+}
+
+// reserve returns true if there are free slots of the trigger
+// namespace and handler, if they are limited by NamespaceConcurrency
+// and HandlerConcurrency, and takes them. Its not waiting for the
+// slots, so triggers of the saturated namespace or handler are left
+// for the next check and don't block firing of other triggers
+func (c *Client) reserve(t *Trigger) bool {
+	ns := c.namespacePools[t.Namespace]
+	if ns != nil && !ns.tryAcquire() {
+		return false
+	}
+	h := c.handlerPools[t.Handler]
+	if h != nil && !h.tryAcquire() {
+		if ns != nil {
+			ns.release()
+		}
+		return false
+	}
+	return true
+}
+
+// unreserve provides releasing of slots which are taken by reserve
+func (c *Client) unreserve(t *Trigger) {
+	if h := c.handlerPools[t.Handler]; h != nil {
+		h.release()
+	}
+	if ns := c.namespacePools[t.Namespace]; ns != nil {
+		ns.release()
+	}
+}
+
+// dispatch provides running of the trigger handler by the worker pool.
+// Slots of the trigger should be taken by reserve. It waits for the free
+// worker, so triggers are started in order of dispatching
+func (c *Client) dispatch(key string, t *Trigger, r *cycle, batch *firedBatch) {
+	c.pool.acquire()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer c.pool.release()
+		defer c.unreserve(t)
+
+		r.fired(c.fire(r.ctx, key, t, batch))
+	}()
+}
+
+// newPools returns worker pools by the limits of namespaces or handlers
+func newPools(limits map[string]int) map[string]*workerPool {
+	pools := make(map[string]*workerPool, len(limits))
+	for ns, limit := range limits {
+		pools[ns] = newWorkerPool(limit)
+	}
+	return pools
+}
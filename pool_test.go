@@ -0,0 +1,70 @@
+package rc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSaturatedNamespaceDoesNotBlockOthers(t *testing.T) {
+	c, _ := newTestClient(t, func(o *ClientOptions) {
+		o.Concurrency = 4
+		o.NamespaceConcurrency = map[string]int{"slow": 1}
+	})
+
+	fastFired := make(chan struct{})
+	blocked := make(chan bool, 2)
+	c.RegisterHandler("slow", func() {
+		select {
+		case <-fastFired:
+			blocked <- false
+		case <-time.After(2 * time.Second):
+			blocked <- true
+		}
+	})
+	c.RegisterHandler("fast", func() { close(fastFired) })
+
+	at := time.Now().Add(-time.Minute)
+	for _, tr := range []*Trigger{
+		{Handler: "slow", Namespace: "slow", DateTime: at},
+		{Handler: "slow", Namespace: "slow", DateTime: at},
+		{Handler: "fast", Namespace: "fast", DateTime: at.Add(time.Second)},
+	} {
+		if err := c.AddTrigger(tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := c.ProcessOnce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Fired != 2 {
+		t.Fatalf("expected 2 fired triggers, got %d", res.Fired)
+	}
+	if <-blocked {
+		t.Fatal("trigger of other namespace was blocked by the saturated namespace")
+	}
+
+	// the trigger of the saturated namespace is left for the next check
+	res, err = c.ProcessOnce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Fired != 1 {
+		t.Fatalf("expected left trigger to be fired, got %d", res.Fired)
+	}
+}
+
+func TestWorkerPoolTryAcquire(t *testing.T) {
+	p := newWorkerPool(1)
+	if !p.tryAcquire() {
+		t.Fatal("expected free worker")
+	}
+	if p.tryAcquire() {
+		t.Fatal("expected saturated pool")
+	}
+	p.release()
+	if !p.tryAcquire() {
+		t.Fatal("expected released worker")
+	}
+}
@@ -0,0 +1,86 @@
+package rc
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis"
+)
+
+// PopReady provides claiming of up to max ready triggers and
+// returns them without calling of handlers. Each trigger is claimed
+// by SREM, so it's returned only to one caller of PopReady. Caller is
+// responsible for processing of triggers. Its intended for clients
+// which don't run Start, since Start fires triggers before removing.
+// Triggers which depend on popped ones are released
+func (c *Client) PopReady(max int) (Triggers, error) {
+	return c.popReady(max, func(key string, t *Trigger) (bool, error) {
+		ok, err := c.claim(key, t)
+		if ok {
+			c.releaseDependents(t.ID)
+		}
+		return ok, err
+	})
+}
+
+// popReady provides claiming of up to max ready triggers by claim
+func (c *Client) popReady(max int, claim func(key string, t *Trigger) (bool, error)) (Triggers, error) {
+
+	readyKeys, err := c.getReadyKeys()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get ready keys: %v", err)
+	}
+
+	var r Triggers
+	now := c.now()
+	for _, k := range readyKeys {
+		var ts Triggers
+		ts, err = c.getTriggers(k)
+		if err != nil {
+			return r, fmt.Errorf("unable to get triggers: %v", err)
+		}
+		ts.sort()
+		for _, t := range ts {
+			if len(r) == max {
+				return r, nil
+			}
+			if t.scheduledTime().After(now) {
+				break
+			}
+			if !c.acceptNamespace(t.Namespace) || t.expired(now) {
+				continue
+			}
+			var claimed bool
+			claimed, err = claim(k, t)
+			if err != nil {
+				return r, fmt.Errorf("unable to claim trigger: %v", err)
+			}
+			if claimed {
+				r = append(r, t)
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// claimScript provides removing of the member from the key
+// and of its ID from the index, if member was in the key
+var claimScript = redis.NewScript(`
+if redis.call("SREM", KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call("HDEL", KEYS[2], ARGV[2])
+return 1
+`)
+
+// claim provides removing of the trigger from the key
+// and returns true if it was removed by this call
+func (c *Client) claim(key string, t *Trigger) (bool, error) {
+
+	n, err := claimScript.Run(c.c, []string{key, c.indexKey}, t.raw, t.ID).Int64()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
@@ -0,0 +1,57 @@
+package rc
+
+import (
+	"fmt"
+	"time"
+)
+
+// PostponeOverdue provides moving of overdue triggers to now+offset
+// instead of firing of them at once, e.g. after a long outage. With
+// ScheduleJitter their times are staggered by the jitter. Each trigger
+// is moved atomically only if its not fired or changed concurrently,
+// so its safe to call while Start is running. Yet trigger which handler
+// is running while its postponed is fired again at the new time.
+// It returns number of postponed triggers
+func (c *Client) PostponeOverdue(offset time.Duration) (int, error) {
+
+	if c.isClosed() {
+		return 0, ErrClosed
+	}
+
+	readyKeys, err := c.getReadyKeys()
+	if err != nil {
+		return 0, fmt.Errorf("unable to get ready keys: %v", err)
+	}
+
+	var postponed int
+	now := c.now()
+	for _, k := range readyKeys {
+		var ts Triggers
+		ts, err = c.getTriggers(k)
+		if err != nil {
+			return postponed, fmt.Errorf("unable to get triggers: %v", err)
+		}
+		for _, t := range ts {
+			if t.scheduledTime().After(now) {
+				continue
+			}
+			next := *t
+			next.DateTime = now.Add(offset)
+			if c.jitter > 0 {
+				next.DateTime = next.DateTime.Add(c.jitterOffset())
+			}
+			next.EarliestStart = time.Time{}
+			next.NextAttempt = time.Time{}
+
+			var ok bool
+			if ok, err = c.compareAndMove(k, t, &next); err != nil {
+				return postponed, err
+			}
+			if ok {
+				postponed++
+			}
+		}
+	}
+
+	return postponed, nil
+}
@@ -0,0 +1,95 @@
+package rc
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ProcessResult defines a result of the one check of ready triggers
+type ProcessResult struct {
+	// Keys is a number of scanned ready keys
+	Keys int
+	// Fired is a number of triggers which handlers were called
+	Fired int
+	// Failed is a number of fired triggers which handlers
+	// returned an error
+	Failed int
+	// Skipped is a number of expired and stale triggers
+	Skipped int
+	// Duration is a duration of the check
+	Duration time.Duration
+}
+
+// ProcessOnce provides the one check of ready triggers
+// and firing of them, the same as Start does on each interval
+func (c *Client) ProcessOnce() (ProcessResult, error) {
+	return c.process(context.Background())
+}
+
+// Tick provides the one check of ready triggers as ProcessOnce
+// and waits until all fired handlers are finished, so it can be used
+// in tests with Clock option instead of Start. Contexts of handlers
+// registered by RegisterContextHandler are derived from ctx
+func (c *Client) Tick(ctx context.Context) (ProcessResult, error) {
+	if err := ctx.Err(); err != nil {
+		return ProcessResult{}, err
+	}
+	return c.process(ctx)
+}
+
+// RunOnce provides firing of all ready triggers and waiting until
+// their handlers are finished, e.g. for the binary which is run
+// periodically by an external scheduler instead of Start. Unlike
+// ProcessOnce, which is the single check, checks are repeated until
+// no trigger is fired or skipped, so FIFO namespaces, retries without
+// RetryDelay and passed occurrences of recurring triggers are drained
+// too. With RateLimit only triggers allowed by it are fired. It returns
+// results of all checks, its Duration is the total duration
+func (c *Client) RunOnce(ctx context.Context) (ProcessResult, error) {
+
+	var total ProcessResult
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		res, err := c.process(ctx)
+		total.Keys += res.Keys
+		total.Fired += res.Fired
+		total.Failed += res.Failed
+		total.Skipped += res.Skipped
+		total.Duration += res.Duration
+		if err != nil {
+			return total, err
+		}
+		if res.Fired == 0 && res.Skipped == 0 || c.noDelete {
+			return total, nil
+		}
+	}
+}
+
+// process provides the one check of ready triggers
+// with the parent context of handlers
+func (c *Client) process(ctx context.Context) (ProcessResult, error) {
+
+	start := time.Now()
+	if c.debounce > 0 {
+		if err := c.commitDebounced(); err != nil {
+			return ProcessResult{Duration: time.Since(start)}, fmt.Errorf("unable to commit debounced triggers: %v", err)
+		}
+	}
+
+	readyKeys, err := c.getReadyKeys()
+	if err != nil {
+		return ProcessResult{Duration: time.Since(start)}, fmt.Errorf("unable to get ready keys: %v", err)
+	}
+	if c.filterReadyKeys != nil {
+		readyKeys = c.filterReadyKeys(readyKeys)
+	}
+
+	res := c.checkReadyKeys(ctx, readyKeys)
+	res.Keys = len(readyKeys)
+	res.Duration = time.Since(start)
+
+	return res, nil
+}
@@ -0,0 +1,77 @@
+package rc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sync/atomic"
+)
+
+// FiredMessage defines a message which is published
+// to PublishChannel when trigger is fired
+type FiredMessage struct {
+	ID        string            `json:"id"`
+	Namespace string            `json:"namespace,omitempty"`
+	Source    string            `json:"source,omitempty"`
+	Payload   json.RawMessage   `json:"payload,omitempty"`
+	Meta      map[string]string `json:"meta,omitempty"`
+}
+
+// Subscribe provides receiving of messages about fired triggers
+// from PublishChannel. fn is called for each message until
+// returned Closer is closed
+func (c *Client) Subscribe(fn func(m *FiredMessage)) (io.Closer, error) {
+
+	if c.publishChannel == "" {
+		return nil, fmt.Errorf("PublishChannel is not defined")
+	}
+
+	ps := c.c.Subscribe(c.publishChannel)
+	if _, err := ps.Receive(); err != nil {
+		ps.Close()
+		return nil, fmt.Errorf("unable to subscribe: %v", err)
+	}
+
+	go func() {
+		for msg := range ps.Channel() {
+			m := &FiredMessage{}
+			if err := json.Unmarshal([]byte(msg.Payload), m); err != nil {
+				log.Printf("unable to unmarshal fired message: %v", err)
+				continue
+			}
+			fn(m)
+		}
+	}()
+
+	return ps, nil
+}
+
+// PublishErrors returns number of failed publishing
+// of messages about fired triggers
+func (c *Client) PublishErrors() uint64 {
+	return atomic.LoadUint64(&c.publishErrors)
+}
+
+// publish provides publishing of the message about
+// fired trigger if PublishChannel is defined
+func (c *Client) publish(t *Trigger) {
+	if c.publishChannel == "" {
+		return
+	}
+
+	m, err := json.Marshal(&FiredMessage{
+		ID:        t.ID,
+		Namespace: t.Namespace,
+		Source:    t.Source,
+		Payload:   t.Payload,
+		Meta:      t.Meta,
+	})
+	if err == nil {
+		err = c.c.Publish(c.publishChannel, m).Err()
+	}
+	if err != nil {
+		atomic.AddUint64(&c.publishErrors, 1)
+		log.Printf("unable to publish fired trigger: %v", err)
+	}
+}
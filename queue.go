@@ -0,0 +1,55 @@
+package rc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis"
+)
+
+// QueueOptions defines options of the queue handler
+type QueueOptions struct {
+	// Key is a key of the Redis list or stream
+	// to which payloads are pushed
+	Key string
+	// Stream provides adding of payloads to the stream with XADD
+	// instead of LPUSH to the list. Entry of the stream has
+	// fields id, namespace and payload
+	Stream bool
+	// MaxLen is an approximate maximum length of the stream.
+	// By default stream is not trimmed
+	MaxLen int64
+}
+
+// RegisterQueueHandler provides registration of the handler by the
+// name which pushes payload of the trigger to the Redis list or stream,
+// so triggers are executed by existing workers instead of the client.
+// Its registered by RegisterContextHandler, so payload is not pushed
+// if the trigger is cancelled before
+func (c *Client) RegisterQueueHandler(name string, options QueueOptions) {
+	c.RegisterContextHandler(name, func(ctx context.Context, t *Trigger) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !options.Stream {
+			if err := c.c.LPush(options.Key, []byte(t.Payload)).Err(); err != nil {
+				return fmt.Errorf("unable to push payload to the list: %v", err)
+			}
+			return nil
+		}
+
+		err := c.c.XAdd(&redis.XAddArgs{
+			Stream:       options.Key,
+			MaxLenApprox: options.MaxLen,
+			Values: map[string]interface{}{
+				"id":        t.ID,
+				"namespace": t.Namespace,
+				"payload":   []byte(t.Payload),
+			},
+		}).Err()
+		if err != nil {
+			return fmt.Errorf("unable to add payload to the stream: %v", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,30 @@
+package rc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestQueueHandler(t *testing.T) {
+	c, m := newTestClient(t)
+	c.RegisterQueueHandler("queue", QueueOptions{Key: "jobs"})
+	fn := c.methods["queue"]
+
+	if err := fn(context.Background(), &Trigger{ID: "a", Payload: json.RawMessage(`{"n":1}`)}); err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := m.List("jobs")
+	if err != nil || len(jobs) != 1 || jobs[0] != `{"n":1}` {
+		t.Fatalf("expected pushed payload, got %v %v", jobs, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err = fn(ctx, &Trigger{ID: "b", Payload: json.RawMessage(`{"n":2}`)}); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if jobs, _ = m.List("jobs"); len(jobs) != 1 {
+		t.Fatalf("expected payload of the cancelled trigger not pushed, got %v", jobs)
+	}
+}
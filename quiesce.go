@@ -0,0 +1,29 @@
+package rc
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrQuiescing is returned on adding of triggers
+// after Quiesce is called
+var ErrQuiescing = errors.New("client is quiescing")
+
+// Quiesce provides stopping of adding of new triggers, so AddTrigger
+// and other methods which add triggers return ErrQuiescing. Start keeps
+// firing of pending triggers, including retries and next occurrences
+// of recurring triggers, so backlog can be drained before Close.
+// Its undone by Resume
+func (c *Client) Quiesce() {
+	atomic.StoreUint32(&c.quiescing, 1)
+}
+
+// Resume provides accepting of new triggers after Quiesce
+func (c *Client) Resume() {
+	atomic.StoreUint32(&c.quiescing, 0)
+}
+
+// Quiescing returns true if Quiesce is called and not resumed
+func (c *Client) Quiescing() bool {
+	return atomic.LoadUint32(&c.quiescing) == 1
+}
@@ -0,0 +1,72 @@
+package rc
+
+import (
+	"fmt"
+	"sync"
+)
+
+// namespaceQuotas defines maximum numbers of pending
+// triggers of namespaces
+type namespaceQuotas struct {
+	mu       sync.RWMutex
+	quotas   map[string]int64
+	fallback int64
+}
+
+func newNamespaceQuotas(quotas map[string]int64, fallback int64) *namespaceQuotas {
+	q := &namespaceQuotas{quotas: map[string]int64{}, fallback: fallback}
+	for ns, n := range quotas {
+		q.quotas[ns] = n
+	}
+	return q
+}
+
+// get returns quota of the namespace, 0 means unlimited
+func (q *namespaceQuotas) get(namespace string) int64 {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if n, ok := q.quotas[namespace]; ok {
+		return n
+	}
+	return q.fallback
+}
+
+// SetNamespaceQuota provides changing of the quota of the namespace
+// at runtime. Zero quota means unlimited, negative one removes
+// the quota of the namespace, so DefaultNamespaceQuota is used
+func (c *Client) SetNamespaceQuota(namespace string, quota int64) {
+	c.quotas.mu.Lock()
+	defer c.quotas.mu.Unlock()
+	if quota < 0 {
+		delete(c.quotas.quotas, namespace)
+		return
+	}
+	c.quotas.quotas[namespace] = quota
+}
+
+// SetDefaultNamespaceQuota provides changing of DefaultNamespaceQuota
+// at runtime. Zero quota means unlimited
+func (c *Client) SetDefaultNamespaceQuota(quota int64) {
+	c.quotas.mu.Lock()
+	defer c.quotas.mu.Unlock()
+	c.quotas.fallback = quota
+}
+
+// checkQuota returns ErrQuotaExceeded if namespace
+// of the trigger has quota pending triggers
+func (c *Client) checkQuota(t *Trigger) error {
+	quota := c.quotas.get(t.Namespace)
+	if quota <= 0 {
+		return nil
+	}
+
+	n, err := c.CountByNamespace(t.Namespace)
+	if err != nil {
+		return fmt.Errorf("unable to count triggers of the namespace: %v", err)
+	}
+	if n >= quota {
+		return ErrQuotaExceeded
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package rc
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/go-redis/redis"
+)
+
+// takeTokenScript provides taking of the token from the token
+// bucket which is refilled by rate tokens per second up to burst
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local b = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(b[1]) or burst
+local ts = tonumber(b[2]) or now
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) * rate / 1000)
+	ts = now
+end
+if tokens < 1 then
+	return 0
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens - 1), "ts", tostring(ts))
+redis.call("PEXPIRE", KEYS[1], ARGV[4])
+return 1
+`)
+
+// rateLimitKey returns key of the token
+// bucket shared by all clients
+func (c *Client) rateLimitKey() string {
+	return fmt.Sprintf("%s:ratelimit", c.prefix)
+}
+
+// allowFire returns true if trigger can be fired by RateLimit.
+// It returns false on error, so trigger is fired on the next check
+func (c *Client) allowFire() bool {
+	if c.rateLimit <= 0 {
+		return true
+	}
+
+	// bucket is removed when its full
+	ttl := int64(math.Ceil(float64(c.rateBurst)/c.rateLimit*1000)) + 1000
+	ok, err := takeTokenScript.Run(c.c, []string{c.rateLimitKey()},
+		c.rateLimit, c.rateBurst, toMillis(c.now()), ttl).Int64()
+	if err != nil {
+		log.Printf("unable to take token of the rate limit: %v", err)
+		return false
+	}
+
+	return ok == 1
+}
+
+// rateBurst returns the burst of the rate limit
+// by default by the rate
+func rateBurst(rate float64, burst int) int {
+	if burst > 0 {
+		return burst
+	}
+	return int(math.Max(1, math.Ceil(rate)))
+}
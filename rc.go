@@ -1,11 +1,17 @@
 package rc
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-redis/redis"
@@ -20,118 +26,1296 @@ var client *Client
 type Triggers []*Trigger
 
 // Client defines a trigger client struct
-// with a redis client
+// with a redis client. Its safe for concurrent use
+// by multiple goroutines, including while Start is running
 type Client struct {
-	c       *redis.Client
-	methods map[string]func()
-	pattern string
+	// counters are accessed atomically and
+	// should be aligned to 64 bits
+	publishErrors uint64
+	firedCount    uint64
+	errorCount    uint64
+	closed        uint32
+	quiescing     uint32
+	started       uint32
+
+	c            *redis.Client
+	pattern      string
+	prefix       string
+	keySeparator string
+	indexKey     string
+	deadKey      string
+	debounce     time.Duration
+
+	archive          bool
+	archiveRetention time.Duration
+
+	retries    int
+	retryDelay time.Duration
+
+	onStartOnce       func()
+	startOnceWindow   time.Duration
+	newID             func() string
+	keyFunc           func(*Trigger) string
+	namespaceKeys     bool
+	bucketSize        int64
+	verifyWrites      bool
+	rejectPast        bool
+	logFiredTriggers  bool
+	noDelete          bool
+	idempotencyWindow time.Duration
+	cronLocation      *time.Location
+	jitter            time.Duration
+	jitterMu          sync.Mutex
+	jitterRand        *rand.Rand
+	source            string
+	maxPending        int64
+	livePending       bool
+	quotas            *namespaceQuotas
+	rateLimit         float64
+	rateBurst         int
+
+	rescheduleRetries    int
+	rescheduleDelay      time.Duration
+	rescheduleDeadLetter bool
+	parseKey             func(string) (time.Time, error)
+	keyMatch             string
+	interval             time.Duration
+	onError              func(error) bool
+	filterReadyKeys      func([]string) []string
+	errLog               *errorLog
+	onCycle              func(time.Duration, int, int64)
+	onAdd                func(*Trigger)
+	onRemove             func(*Trigger)
+	crypt                *payloadCrypt
+	done                 chan struct{}
+	stop                 chan struct{}
+	monitors             sync.WaitGroup
+
+	publishChannel     string
+	adaptive           bool
+	safetyPollInterval time.Duration
+	onSkip             func(*Trigger, string)
+	dropStale          bool
+
+	clock         func() time.Time
+	useServerTime bool
+	clockMu       sync.Mutex
+	clockOffset   time.Duration
+	clockSynced   time.Time
+
+	includeNamespaces map[string]struct{}
+	excludeNamespaces map[string]struct{}
+	fifoNamespaces    map[string]struct{}
+
+	pool           *workerPool
+	namespacePools map[string]*workerPool
+	handlerPools   map[string]*workerPool
+	metrics        MetricsSink
+
+	lagThreshold int64
+	lagDuration  time.Duration
+	onLag        func(int64)
+	lagSince     time.Time
+	lagReported  bool
+
+	methodsMu  sync.RWMutex
+	methods    map[string]func(context.Context, *Trigger) error
+	nextFuncs  map[string]func(time.Time) time.Time
+	validators map[string]func(json.RawMessage) error
+
+	runningMu sync.Mutex
+	running   map[string]*runningHandler
+
+	// foreignKeys are keys which match KeyMatch,
+	// but can't be parsed and are logged
+	foreignKeys sync.Map
+
+	resultsMu sync.Mutex
+	results   map[string][]chan error
+
+	statsMu   sync.Mutex
+	stats     Stats
+	lastErr   error
+	lastErrAt time.Time
 }
 
-// Trigger defines a struct for trigger of schedules
+// Trigger defines a struct for trigger of schedules.
+// Its stored in Redis as compact JSON, empty optional
+// fields are omitted
 type Trigger struct {
-	DateTime  time.Time
-	Namespace string
-	Func      func()
+	// ID is an unique id of the trigger.
+	// Its generated by AddTrigger if its empty
+	ID        string    `json:"id"`
+	DateTime  time.Time `json:"date_time"`
+	Namespace string    `json:"namespace,omitempty"`
+	// Handler is a name of the registered handler
+	// which is called when trigger is fired
+	Handler string `json:"handler,omitempty"`
+	Func    func() `json:"-"`
+	// Payload is a JSON data which is passed to the handler
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// DedupKey is a key of the logical job. With Debounce option
+	// triggers with the same DedupKey which are added within
+	// the debounce window are collapsed to the last one
+	DedupKey string `json:"dedup_key,omitempty"`
+	// CoalesceKey is a key of the logical job within the key of
+	// the trigger. Trigger with CoalesceKey is not inserted if there is
+	// a pending trigger with it in the same key of the same second,
+	// ID of the trigger is set to ID of the pending one instead (first
+	// write wins). Unlike DedupKey it doesn't depend on Debounce and
+	// doesn't collapse triggers of different keys
+	CoalesceKey string `json:"coalesce_key,omitempty"`
+	// Labels are arbitrary key/value pairs for
+	// filtering of triggers, see ListByLabel
+	Labels map[string]string `json:"labels,omitempty"`
+	// Meta is an opaque data of the caller, e.g. trace ID, which is
+	// not interpreted by the client. Its passed back to handlers, in the
+	// context of handlers by MetaFromContext, and to FiredMessage and FiredLog
+	Meta map[string]string `json:"meta,omitempty"`
+	// Replay is set for triggers which are scheduled by Replay.
+	// Such triggers are not archived again
+	Replay bool `json:"replay,omitempty"`
+	// Attempt is a number of retries of the trigger after
+	// failures of its handler. Its 0 for the first firing
+	Attempt int `json:"attempt,omitempty"`
+	// MaxAttempts is a maximum number of firings of the trigger
+	// including retries. By default its Retries of the client plus one.
+	// Trigger which is failed on the last attempt is moved
+	// to the dead-letter set
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// NextAttempt is a time of the next retry of the failed trigger
+	NextAttempt time.Time `json:"next_attempt"`
+	// Next is a name of the function registered by RegisterNextFunc.
+	// Trigger with Next is recurring: after its firing its scheduled
+	// again on the time returned by the function
+	Next string `json:"next,omitempty"`
+	// CronSpec is a standard cron spec of the recurring trigger,
+	// see AddCron. Its used instead of Next
+	CronSpec string `json:"cron_spec,omitempty"`
+	// CronLocation is a name of the IANA location, e.g. "Europe/Berlin",
+	// in which CronSpec is evaluated. By default its CronLocation
+	// of the client
+	CronLocation string `json:"cron_location,omitempty"`
+	// Overlap is a policy of the recurring trigger which handler
+	// is running longer than its interval, see OverlapQueue,
+	// OverlapSkip and OverlapConcurrent. By default its OverlapQueue.
+	// Client doesn't fire the trigger while its handler of the trigger
+	// with the same ID is running, unless its OverlapConcurrent
+	Overlap string `json:"overlap,omitempty"`
+	// EarliestStart is a time before which trigger is not fired
+	// even if DateTime is passed
+	EarliestStart time.Time `json:"earliest_start"`
+	// Deadline is a time after which not fired trigger
+	// is moved to the dead-letter set as expired
+	Deadline time.Time `json:"deadline"`
+	// ExpireAt is a time after which not fired trigger is removed by
+	// Redis even if its not checked by any client. Redis can't expire
+	// members of the SET, so such trigger is stored in its own key
+	// <key>:expiring:<id> with the expiry instead of the shared key
+	// of its time. Its found by KeyMatch as another keys, so KeyMatch
+	// of KeyFunc should match it too. Expired trigger is removed
+	// silently, use Deadline to move it to the dead-letter set
+	ExpireAt time.Time `json:"expire_at"`
+	// MaxStaleness is a maximum delay of firing after the
+	// scheduled time. Trigger which is ready later is not fired
+	// and dropped or moved to the dead-letter set as stale
+	MaxStaleness time.Duration `json:"max_staleness,omitempty"`
+	// DependsOn is a list of IDs of triggers after which completion
+	// trigger is scheduled. It's held until all of them are fired
+	// successfully, then it's fired at DateTime or immediately
+	// if DateTime is passed. Cancelled, popped by PopReady and
+	// acknowledged by Ack dependencies are completed too. Failed
+	// dependency blocks the trigger until its retries succeed.
+	// Dependencies can't have a cycle
+	DependsOn []string `json:"depends_on,omitempty"`
+	// Source defines service or host which added the trigger.
+	// With AutoSource option its set to <hostname>:<pid> if its empty
+	Source string `json:"source,omitempty"`
+	// Seq is a sequence number of the trigger of the namespace
+	// from FIFONamespaces. Its set on adding
+	Seq int64 `json:"seq,omitempty"`
+
+	// raw is a member of the Redis SET from which
+	// trigger was decoded
+	raw string
 }
 
 func (t *Trigger) encode() ([]byte, error) {
 	return json.Marshal(t)
 }
 
+// scheduledTime returns time when trigger becomes ready
+func (t *Trigger) scheduledTime() time.Time {
+	r := t.DateTime
+	if t.EarliestStart.After(r) {
+		r = t.EarliestStart
+	}
+	if t.NextAttempt.After(r) {
+		r = t.NextAttempt
+	}
+	return r
+}
+
+// expired returns true if deadline of the trigger is passed
+func (t *Trigger) expired(now time.Time) bool {
+	return !t.Deadline.IsZero() && now.After(t.Deadline)
+}
+
+// maxAttempts returns MaxAttempts of the trigger
+// or the default one by the retries of the client
+func (t *Trigger) maxAttempts(retries int) int {
+	if t.MaxAttempts > 0 {
+		return t.MaxAttempts
+	}
+	return retries + 1
+}
+
+// stale returns true if trigger is ready later
+// than its MaxStaleness
+func (t *Trigger) stale(now time.Time) bool {
+	return t.MaxStaleness > 0 && now.Sub(t.scheduledTime()) > t.MaxStaleness
+}
+
+// String returns indented JSON of the trigger
+// for debugging and logging
+func (t *Trigger) String() string {
+	b, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("unable to marshal trigger: %v", err)
+	}
+	return string(b)
+}
+
 // ClientOptions defines a trigger options
 // with redis options
 type ClientOptions struct {
+	// Options are options of the Redis client. If PoolSize is not set,
+	// its 10 per CPU but not less than Concurrency plus 4 connections of
+	// the poll loop. If MinIdleConns is not set, 2 connections are kept open
 	Options redis.Options
+	// Pattern is a prefix of the keys which holds triggers.
+	// Keys are stored as <pattern>-<unix timestamp>
 	Pattern string
+	// KeySeparator is a separator of parts of the default keys
+	// <pattern><separator><unix timestamp>. Time of the key is parsed
+	// after its last separator, so pattern and namespaces can contain it.
+	// It can't start with ":", which is used by keys of the client,
+	// e.g. <pattern>:ids, and can't contain digits or glob characters.
+	// Changing of it for existing triggers makes them not found.
+	// By default its "-", which is kept for existing keys. Keys of the
+	// pattern match keys of patterns which start with the pattern and
+	// the separator, e.g. client of "my" fires triggers of "my-app".
+	// Such patterns should be used with another separator, e.g. "/"
+	KeySeparator string
+	// KeyFunc returns the key of the set which holds the trigger.
+	// It replaces the default <pattern>-<unix timestamp> scheme
+	// and should be defined together with ParseKey and KeyMatch
+	KeyFunc func(t *Trigger) string
+	// ParseKey returns the scheduled time of the key made by KeyFunc
+	ParseKey func(key string) (time.Time, error)
+	// KeyMatch is a glob pattern which matches all keys made
+	// by KeyFunc. By default its <pattern>-*
+	KeyMatch string
+	// NamespaceKeys provides storing of triggers in keys
+	// <pattern>-<namespace>-<unix timestamp>, so ListByNamespace and
+	// CountByNamespace read only keys of the namespace instead of
+	// decoding of all triggers. Its a tradeoff: number of keys is
+	// multiplied by number of namespaces, so each check of ready
+	// triggers reads more keys. Keys of the default scheme are
+	// still processed, so it can be enabled for existing triggers.
+	// It can't be used with KeyFunc
+	NamespaceKeys bool
+	// BucketSize is a maximum number of triggers in the key.
+	// When the key is full, triggers are spilled into its overflow
+	// keys <key>:overflow:1, <key>:overflow:2 and so on, which are
+	// read as the key itself. Overflow keys are found by KeyMatch, so
+	// KeyMatch of KeyFunc should match them too. By default its unlimited
+	BucketSize int64
+	// MaxPending is a maximum number of pending triggers. When its
+	// exceeded, adding of triggers returns ErrBacklogFull. By default
+	// number of pending triggers is taken from Stats, which is updated
+	// by each check of Start, so its not checked without Start.
+	// By default its unlimited
+	MaxPending int64
+	// LivePending provides counting of pending triggers for MaxPending
+	// on each adding instead of Stats. It reads all keys of triggers
+	LivePending bool
+	// NamespaceQuotas are maximum numbers of pending triggers by
+	// namespaces. When namespace has its quota of pending triggers,
+	// adding of triggers to it returns ErrQuotaExceeded. Triggers are
+	// counted by CountByNamespace on each adding, so its cheap only
+	// with NamespaceKeys. Quotas can be changed by SetNamespaceQuota
+	NamespaceQuotas map[string]int64
+	// DefaultNamespaceQuota is a quota of namespaces which are not
+	// in NamespaceQuotas, including the empty one. By default its unlimited
+	DefaultNamespaceQuota int64
+	// AutoSource provides setting of Source of added triggers
+	// to <hostname>:<pid> of the process if its empty
+	AutoSource bool
+	// LogFired provides logging of the structured JSON record
+	// for each fired trigger, see FiredLog
+	LogFired bool
+	// NoDelete is a diagnostic option which provides firing of triggers
+	// without removing of them, so they are fired again by each check.
+	// Retries, dead-lettering and next occurrences of recurring triggers
+	// are disabled too. Its intended only for short debugging of
+	// double firing with FiredCount or LogFired, never use it in production
+	NoDelete bool
+	// IdempotencyWindow is a duration during which fired triggers are
+	// recorded in the ledger <pattern>:ledger, so the trigger which is
+	// found again, e.g. because the client is crashed before its removing,
+	// is removed or rescheduled without calling of its handler. Retries
+	// and next occurrences of recurring triggers are not skipped. Firing is
+	// recorded before the handler is called, so trigger which handler is
+	// interrupted by the crash is not fired again within the window too.
+	// Its best effort: triggers are fired again after the window or
+	// when the ledger is not available. By default its disabled
+	IdempotencyWindow time.Duration
+	// CronLocation is a location in which CronSpec of triggers without
+	// CronLocation is evaluated, so occurrences keep the local time
+	// after DST transitions. By default its time.Local
+	CronLocation *time.Location
+	// ScheduleJitter provides random offset of DateTime of the added
+	// trigger by up to ±ScheduleJitter, so triggers which are added for
+	// the same time are spread across keys. Replayed, imported and
+	// recurring triggers with CronSpec or Next are not offset, since
+	// next occurrences are computed from DateTime
+	ScheduleJitter time.Duration
+	// JitterRand is a random source of ScheduleJitter.
+	// By default its seeded by the current time
+	JitterRand *rand.Rand
+	// RejectPast provides rejecting of triggers with DateTime
+	// in the past by adding methods with ErrPast. By default such
+	// triggers are fired on the next check. Replayed triggers
+	// are not rejected
+	RejectPast bool
+	// VerifyWrites provides checking that each added trigger
+	// is stored in its key with SISMEMBER after the write. Adding
+	// returns ErrWriteLost if its missing. Note, that ready trigger
+	// can be fired by the other client before the check
+	VerifyWrites bool
+	// HashTag provides storing of keys as {<pattern>}-<unix timestamp>
+	// so all keys are placed on the same slot of Redis Cluster
+	// and can be used together in scripts and transactions.
+	// Note, that all triggers are stored on the one node
+	HashTag bool
+	// Retries is a number of retries of the trigger which handler
+	// returned an error. Trigger which is failed after all retries
+	// is moved to the dead-letter set. Its a default for triggers
+	// without MaxAttempts. Without retries failed
+	// trigger is removed
+	Retries int
+	// RetryDelay is a duration between the failure
+	// of the trigger and its retry
+	RetryDelay time.Duration
+	// RescheduleRetries is a number of retries of failed scheduling
+	// of the next occurrence of the recurring trigger. Delay between
+	// retries starts from RescheduleDelay and is doubled each time
+	RescheduleRetries int
+	// RescheduleDelay is a delay before the first retry of
+	// scheduling of the next occurrence. By default its 100ms
+	RescheduleDelay time.Duration
+	// RescheduleDeadLetter provides moving of the recurring trigger
+	// which next occurrence can't be scheduled after all retries to
+	// the dead-letter set with ReasonRescheduleFailed. Otherwise its
+	// left in its key and fired again on the next check. In both
+	// cases ErrorHandler is called with RescheduleError, its result
+	// is ignored. Without ErrorHandler error is logged
+	RescheduleDeadLetter bool
+	// OnStartOnce is called by Start of only one client of all
+	// clients with the same pattern. Its coordinated with the
+	// <pattern>:once key, which is kept for StartOnceWindow
+	// (forever by default) or until ResetStartOnce is called
+	OnStartOnce     func()
+	StartOnceWindow time.Duration
+	// Archive provides storing of fired triggers in the
+	// <pattern>:archive sorted set by the time of firing,
+	// so they can be fired again by Replay
+	Archive bool
+	// ArchiveRetention is a duration after which fired triggers
+	// are removed from the archive. By default they are kept forever
+	ArchiveRetention time.Duration
+	// IDGenerator returns ID of the trigger which is added
+	// without ID. By default its a random UUID
+	IDGenerator func() string
+	// Debounce is a window during which triggers with
+	// the same DedupKey are collapsed. See AddTrigger
+	Debounce time.Duration
+	// Clock returns the current local time. Its intended for tests
+	// with Tick, by default its time.Now
+	Clock func() time.Time
+	// Interval is a duration between checks of ready triggers.
+	// By default its one second
+	Interval time.Duration
+	// Adaptive provides sleeping of Start until the next trigger
+	// instead of checking on each interval. Clients with this option
+	// publish the time of added triggers to the <pattern>:notify
+	// channel, so Start is woken up by the earlier trigger.
+	// If subscription is failed, Interval is used
+	Adaptive bool
+	// SafetyPollInterval is a maximum duration of sleeping of Start
+	// in the Adaptive mode, so triggers are checked even if
+	// notifications are lost. By default its 1 minute
+	SafetyPollInterval time.Duration
+	// SkipPing disables checking of the connection
+	// to Redis on creating of the client. Connection is
+	// established on the first command
+	SkipPing bool
+	// ErrorHandler is called on error of the checking
+	// of ready triggers. If it returns true, Start is stopped.
+	// By default error is logged and Start is continued
+	ErrorHandler func(err error) (stop bool)
+	// ErrorLogWindow is a window in which identical consecutive
+	// errors of the poll loop are collapsed into a single line with
+	// a count, if ErrorHandler is not set. By default its 1 minute.
+	// Negative value disables collapsing
+	ErrorLogWindow time.Duration
+	// OnCycle is called at the end of each check of ready triggers
+	// with its duration, number of fired triggers and number
+	// of pending triggers
+	OnCycle func(cycleDuration time.Duration, readyCount int, pending int64)
+	// OnAdd is called synchronously after the trigger is added
+	// by this client. Triggers which are added by other clients
+	// or by the recurring of fired triggers are not passed
+	OnAdd func(t *Trigger)
+	// OnRemove is called synchronously after the trigger is removed
+	// by RemoveTrigger, Cancel or CancelMany of this client.
+	// Fired triggers and removals by other clients are not passed
+	OnRemove func(t *Trigger)
+	// EncryptionKeys are AES keys of 16, 24 or 32 bytes by their versions.
+	// If its set, payloads of triggers are encrypted by AES-GCM with
+	// the key of EncryptionKeyVersion before storing and decrypted by
+	// the key of the version from their header on reading, so old keys
+	// should be kept until triggers encrypted by them are fired.
+	// Only payloads are encrypted, other fields are stored as is.
+	// Payloads which are published to PublishChannel or pushed
+	// by queue handlers are not encrypted.
+	// Keys are not stored in Redis, caller is responsible for keeping
+	// them secret and the same on all clients of the Pattern
+	EncryptionKeys map[byte][]byte
+	// EncryptionKeyVersion is a version of the key from EncryptionKeys
+	// which is used for encryption of new triggers
+	EncryptionKeyVersion byte
+	// Concurrency is a number of handlers which are run
+	// concurrently. By default handlers are run one by one.
+	// It can be changed by SetConcurrency
+	Concurrency int
+	// NamespaceConcurrency defines maximum number of concurrently
+	// running handlers of triggers by the namespace. Triggers of the
+	// saturated namespace are left for the next check, so they don't
+	// block triggers of other namespaces. Namespace with
+	// the limit 1 is serialized
+	NamespaceConcurrency map[string]int
+	// HandlerConcurrency defines maximum number of concurrently
+	// running handlers by their names, in addition to Concurrency.
+	// Triggers of the saturated handler are left for the next check
+	HandlerConcurrency map[string]int
+	// Metrics is a backend of metrics of the client,
+	// see MetricsSink for their names. By default metrics are dropped
+	Metrics MetricsSink
+	// RateLimit is a maximum number of triggers which are fired per
+	// second by all clients with the same Pattern. Its a token bucket
+	// stored in Redis, so it takes a command for each trigger.
+	// When the bucket is empty, rest of ready triggers are fired
+	// on the next check. By default its unlimited
+	RateLimit float64
+	// RateBurst is a size of the token bucket of RateLimit.
+	// By default its RateLimit
+	RateBurst int
+	// FIFONamespaces defines namespaces which triggers are fired
+	// strictly in order of adding, even if they are stored in the
+	// same key or trigger which is added later has the earlier time.
+	// Trigger is fired only after all triggers of the namespace which
+	// were added before it are fired, cancelled or moved to the dead-letter
+	// set, so failed trigger blocks the namespace until its retries are
+	// finished. Only one trigger of such namespace is fired by each check
+	// of ready triggers, so they are never run concurrently. Recurring,
+	// debounced and dependent triggers are not ordered
+	FIFONamespaces []string
+	// FilterReadyKeys is called on each check with ready keys sorted
+	// from the oldest one and returns keys which are processed by
+	// the client, so keys can be partitioned or locked between clients.
+	// If it returns nil, all keys are skipped on this check
+	FilterReadyKeys func(keys []string) []string
+	// IncludeNamespaces defines namespaces of triggers which
+	// are fired by this client. If its empty, triggers
+	// of all namespaces are fired
+	IncludeNamespaces []string
+	// ExcludeNamespaces defines namespaces of triggers which
+	// are not fired by this client. Such triggers are left
+	// in Redis for another clients
+	ExcludeNamespaces []string
+	// UseServerTime provides using of the Redis server time
+	// instead of the local time for checking of ready triggers,
+	// so all clients agree on the current time regardless
+	// of the clock skew between them
+	UseServerTime bool
+	// OnSkip is called when ready trigger is not fired
+	// because of the reason (ReasonExpired or ReasonStale)
+	OnSkip func(t *Trigger, reason string)
+	// DropStale provides removing of stale triggers instead
+	// of moving them to the dead-letter set
+	DropStale bool
+	// PublishChannel is a Redis pub/sub channel to which
+	// FiredMessage is published when trigger is fired.
+	// Publishing is best-effort, failures are counted
+	// by PublishErrors and do not affect firing
+	PublishChannel string
+	// OnLag is called when number of overdue triggers stays
+	// above LagThreshold longer than LagDuration. Its called
+	// once until number of overdue triggers is recovered
+	OnLag        func(overdue int64)
+	LagThreshold int64
+	LagDuration  time.Duration
 }
 
+// Option provides changing of the client options
+// before creating of the client
+type Option func(*ClientOptions)
+
 // New provides init of the new trigger client
-func New(options *ClientOptions) *Client {
+func New(options *ClientOptions, opts ...Option) *Client {
+
+	c, err := newClient(options, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+
+}
 
-	c := redis.NewClient(&options.Options)
-	_, err := c.Ping().Result()
+// NewFromURL provides init of the new trigger client
+// from the connection string like redis://:password@host:port/db
+func NewFromURL(url string, opts ...Option) (*Client, error) {
+
+	redisOptions, err := redis.ParseURL(url)
 	if err != nil {
-		panic(fmt.Errorf("unable to ping redis: %v", err))
+		return nil, fmt.Errorf("unable to parse url: %v", err)
 	}
+
+	return newClient(&ClientOptions{Options: *redisOptions}, opts...)
+
+}
+
+func newClient(options *ClientOptions, opts ...Option) (*Client, error) {
+
+	for _, o := range opts {
+		o(options)
+	}
+
 	pattern := options.Pattern
 	if pattern == "" {
-		pattern = "rc-*"
+		pattern = "rc"
 	}
-	return &Client{
-		c:       c,
-		methods: map[string]func(){},
-		pattern: pattern,
+	interval := options.Interval
+	if interval == 0 {
+		interval = 1 * time.Second
+	}
+	rescheduleDelay := options.RescheduleDelay
+	if rescheduleDelay == 0 {
+		rescheduleDelay = 100 * time.Millisecond
+	}
+	clock := options.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	safetyPollInterval := options.SafetyPollInterval
+	if safetyPollInterval == 0 {
+		safetyPollInterval = time.Minute
+	}
+	errorLogWindow := options.ErrorLogWindow
+	if errorLogWindow == 0 {
+		errorLogWindow = defaultErrorLogWindow
+	}
+	prefix := pattern
+	if options.HashTag {
+		prefix = fmt.Sprintf("{%s}", pattern)
+	}
+	keyFunc, parseKey, keyMatch := options.KeyFunc, options.ParseKey, options.KeyMatch
+	if keyFunc != nil && options.NamespaceKeys {
+		return nil, fmt.Errorf("NamespaceKeys can't be used with KeyFunc")
+	}
+	keySeparator := options.KeySeparator
+	if keySeparator == "" {
+		keySeparator = "-"
+	}
+	if strings.HasPrefix(keySeparator, ":") || strings.ContainsAny(keySeparator, "0123456789*?[]\\") {
+		return nil, fmt.Errorf("invalid KeySeparator %q", keySeparator)
+	}
+	if keyFunc == nil {
+		keyFunc = defaultKeyFunc(prefix, keySeparator)
+		if options.NamespaceKeys {
+			keyFunc = namespaceKeyFunc(prefix, keySeparator)
+		}
+		parseKey = defaultParseKey(prefix, keySeparator)
+		keyMatch = globEscape(prefix+keySeparator) + "*"
+	}
+	if parseKey == nil || keyMatch == "" {
+		return nil, fmt.Errorf("ParseKey and KeyMatch should be defined with KeyFunc")
+	}
+	metrics := options.Metrics
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+	crypt, err := newPayloadCrypt(options.EncryptionKeys, options.EncryptionKeyVersion)
+	if err != nil {
+		return nil, err
+	}
+	cronLocation := options.CronLocation
+	if cronLocation == nil {
+		cronLocation = time.Local
+	}
+	jitterRand := options.JitterRand
+	if jitterRand == nil {
+		jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	var source string
+	if options.AutoSource {
+		source = processSource()
+	}
+	idGenerator := options.IDGenerator
+	if idGenerator == nil {
+		idGenerator = newID
 	}
 
+	// client is created after validation of options,
+	// so its not leaked if they are invalid
+	c := redis.NewClient(poolOptions(options.Options, options.Concurrency))
+	if !options.SkipPing {
+		if err = c.Ping().Err(); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("unable to ping redis: %v", err)
+		}
+	}
+	return &Client{
+		c:            c,
+		methods:      map[string]func(context.Context, *Trigger) error{},
+		running:      map[string]*runningHandler{},
+		validators:   map[string]func(json.RawMessage) error{},
+		nextFuncs:    map[string]func(time.Time) time.Time{},
+		results:      map[string][]chan error{},
+		pattern:      pattern,
+		prefix:       prefix,
+		indexKey:     fmt.Sprintf("%s:ids", prefix),
+		keySeparator: keySeparator,
+		deadKey:      fmt.Sprintf("%s:dead", prefix),
+		debounce:     options.Debounce,
+
+		archive:          options.Archive,
+		archiveRetention: options.ArchiveRetention,
+
+		retries:    options.Retries,
+		retryDelay: options.RetryDelay,
+
+		onStartOnce:       options.OnStartOnce,
+		startOnceWindow:   options.StartOnceWindow,
+		newID:             idGenerator,
+		keyFunc:           keyFunc,
+		namespaceKeys:     options.NamespaceKeys,
+		bucketSize:        options.BucketSize,
+		verifyWrites:      options.VerifyWrites,
+		rejectPast:        options.RejectPast,
+		logFiredTriggers:  options.LogFired,
+		noDelete:          options.NoDelete,
+		idempotencyWindow: options.IdempotencyWindow,
+		cronLocation:      cronLocation,
+		jitter:            options.ScheduleJitter,
+		jitterRand:        jitterRand,
+		source:            source,
+		maxPending:        options.MaxPending,
+		quotas:            newNamespaceQuotas(options.NamespaceQuotas, options.DefaultNamespaceQuota),
+		livePending:       options.LivePending,
+		rateLimit:         options.RateLimit,
+		rateBurst:         rateBurst(options.RateLimit, options.RateBurst),
+
+		rescheduleRetries:    options.RescheduleRetries,
+		rescheduleDelay:      rescheduleDelay,
+		rescheduleDeadLetter: options.RescheduleDeadLetter,
+		parseKey:             parseKey,
+		keyMatch:             keyMatch,
+		interval:             interval,
+		onError:              options.ErrorHandler,
+		errLog:               &errorLog{window: errorLogWindow},
+		onCycle:              options.OnCycle,
+		onAdd:                options.OnAdd,
+		onRemove:             options.OnRemove,
+		crypt:                crypt,
+		filterReadyKeys:      options.FilterReadyKeys,
+		done:                 make(chan struct{}),
+		stop:                 make(chan struct{}),
+
+		publishChannel:     options.PublishChannel,
+		adaptive:           options.Adaptive,
+		safetyPollInterval: safetyPollInterval,
+		onSkip:             options.OnSkip,
+		dropStale:          options.DropStale,
+		clock:              clock,
+		useServerTime:      options.UseServerTime,
+
+		includeNamespaces: namespacesSet(options.IncludeNamespaces),
+		excludeNamespaces: namespacesSet(options.ExcludeNamespaces),
+		fifoNamespaces:    namespacesSet(options.FIFONamespaces),
+
+		pool:           newWorkerPool(options.Concurrency),
+		namespacePools: newPools(options.NamespaceConcurrency),
+		handlerPools:   newPools(options.HandlerConcurrency),
+		metrics:        metrics,
+
+		lagThreshold: options.LagThreshold,
+		lagDuration:  options.LagDuration,
+		onLag:        options.OnLag,
+	}, nil
+
 }
 
 // AddTrigger provides append inserting of the new trigger
 // to the Redis SET. Its based on the key
-// empty-slots-timestamp and namespace
+// pattern-timestamp. If ID of the trigger is empty,
+// its generated and set to the trigger.
+//
+// With Debounce option trigger with DedupKey is not inserted
+// immediately. Its held for the debounce window and replaced
+// by the next trigger with the same DedupKey, if its added
+// within the window (last write wins). The last trigger is inserted
+// by Start when the window is passed without new triggers
 func (c *Client) AddTrigger(t *Trigger) error {
 
-	encodedT, err := t.encode()
+	encodedT, err := c.encodeTrigger(t)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.add(t, encodedT)
+	return err
+
+}
+
+// ScheduleResult defines the scheduled trigger
+type ScheduleResult struct {
+	// ID is an ID of the trigger
+	ID string
+	// Key is a key of the Redis SET in which trigger is stored.
+	// For debounced or waiting for dependencies trigger its
+	// the key in which trigger will be stored
+	Key string
+}
+
+// Schedule provides adding of the copy of the trigger as AddTrigger
+// and returns its assigned ID and key. The trigger is not changed
+func (c *Client) Schedule(t *Trigger) (ScheduleResult, error) {
+
+	nt := *t
+	encodedT, err := c.encodeTrigger(&nt)
 	if err != nil {
-		return fmt.Errorf("unable to marshal trigger: %v", err)
+		return ScheduleResult{}, err
 	}
 
-	cmd := c.c.SAdd(fmt.Sprintf("ns-%s",
-		getUnixTimeString(t.DateTime)),
-		encodedT,
+	key, err := c.add(&nt, encodedT)
+	if err != nil {
+		return ScheduleResult{}, err
+	}
+
+	return ScheduleResult{ID: nt.ID, Key: key}, nil
+}
+
+// add provides storing of the encoded trigger
+// and returns the key in which its stored
+func (c *Client) add(t *Trigger, encodedT []byte) (string, error) {
+	var (
+		key string
+		err error
 	)
-	if cmd.Err() != nil {
-		return fmt.Errorf("unable to insert trigger: %v", cmd.Err())
+	switch {
+	case len(t.DependsOn) > 0:
+		key, err = c.keyFunc(t), c.addDependent(t, encodedT)
+	case c.debounce > 0 && t.DedupKey != "":
+		key, err = c.keyFunc(t), c.addDebounced(t, encodedT)
+	case t.CoalesceKey != "":
+		var inserted bool
+		if key, inserted, err = c.addCoalesced(t, encodedT); err == nil && !inserted {
+			return key, nil
+		}
+	default:
+		key, err = c.insert(t, encodedT)
+	}
+	if err != nil {
+		return key, err
+	}
+	c.added(t)
+	return key, nil
+}
+
+// added provides calling of OnAdd with the added trigger
+func (c *Client) added(t *Trigger) {
+	c.metrics.IncCounter(MetricTriggersAdded, map[string]string{"namespace": t.Namespace})
+	if c.onAdd != nil {
+		c.onAdd(t)
+	}
+}
+
+// removed provides calling of OnRemove with the removed trigger
+func (c *Client) removed(t *Trigger) {
+	c.metrics.IncCounter(MetricTriggersRemoved, map[string]string{"namespace": t.Namespace})
+	if c.onRemove != nil {
+		c.onRemove(t)
+	}
+}
+
+// encodeTrigger provides generating of the trigger ID
+// if its empty and encoding of the trigger
+func (c *Client) encodeTrigger(t *Trigger) ([]byte, error) {
+
+	if c.isClosed() {
+		return nil, ErrClosed
+	}
+	if c.Quiescing() {
+		return nil, ErrQuiescing
+	}
+
+	if t.CronSpec != "" {
+		if _, err := c.cronNext(t); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.validate(t); err != nil {
+		return nil, err
+	}
+	switch t.Overlap {
+	case "", OverlapQueue, OverlapSkip, OverlapConcurrent:
+	default:
+		return nil, fmt.Errorf("unknown overlap policy %q", t.Overlap)
+	}
+	if c.rejectPast && !t.Replay && t.DateTime.Before(c.now()) {
+		return nil, ErrPast
+	}
+	if c.jitter > 0 && !t.Replay && t.CronSpec == "" && t.Next == "" {
+		t.DateTime = t.DateTime.Add(c.jitterOffset())
+	}
+	if err := c.checkBacklog(); err != nil {
+		return nil, err
+	}
+	if err := c.checkQuota(t); err != nil {
+		return nil, err
+	}
+
+	if t.ID == "" {
+		t.ID = c.newID()
+	}
+	if t.Source == "" {
+		t.Source = c.source
+	}
+	if err := c.sequence(t); err != nil {
+		return nil, err
+	}
+	if t.MaxAttempts == 0 && c.retries > 0 {
+		t.MaxAttempts = c.retries + 1
+	}
+
+	encodedT, err := c.encode(t)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal trigger: %v", err)
+	}
+
+	return encodedT, nil
+
+}
+
+// insert provides inserting of the encoded trigger
+// and its key to the index of IDs. It returns the key
+func (c *Client) insert(t *Trigger, encodedT []byte) (string, error) {
+
+	key, err := c.storeKey(t)
+	if err != nil {
+		return "", c.closedOr(err)
+	}
+	_, err = c.c.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.SAdd(key, encodedT)
+		c.expire(pipe, key, t)
+		pipe.HSet(c.indexKey, t.ID, key)
+		c.addSequenced(pipe, t)
+		return nil
+	})
+	if err != nil {
+		return "", c.closedOr(fmt.Errorf("unable to insert trigger: %v", err))
+	}
+	if err = c.verifyWrite(key, encodedT); err != nil {
+		return "", err
+	}
+	c.notify(t)
+
+	return key, nil
+
+}
+
+// verifyWrite provides checking with SISMEMBER that the trigger
+// is stored in the key, if VerifyWrites option is set
+func (c *Client) verifyWrite(key string, encodedT []byte) error {
+	if !c.verifyWrites {
+		return nil
+	}
+
+	ok, err := c.c.SIsMember(key, encodedT).Result()
+	if err != nil {
+		return c.closedOr(fmt.Errorf("unable to verify write of the trigger: %v", err))
+	}
+	if !ok {
+		return ErrWriteLost
 	}
 
 	return nil
+}
+
+// RegisterHandler provides registration of the handler by the name.
+// Triggers with the same Handler calls fn when they are fired.
+// Its safe to register handlers while Start is running
+func (c *Client) RegisterHandler(name string, fn func()) {
+	c.RegisterTriggerHandler(name, func(*Trigger) error {
+		fn()
+		return nil
+	})
+}
+
+// RegisterTriggerHandler provides registration of the handler
+// by the name. Handler receives fired trigger and its error
+// is logged and passed to the result of AddTriggerWithResult
+func (c *Client) RegisterTriggerHandler(name string, fn func(t *Trigger) error) {
+	c.RegisterContextHandler(name, func(_ context.Context, t *Trigger) error {
+		return fn(t)
+	})
+}
 
+// UnregisterHandler provides removing of the handler by the name.
+// Triggers with this Handler are left in Redis until
+// the handler is registered again
+func (c *Client) UnregisterHandler(name string) {
+	c.methodsMu.Lock()
+	defer c.methodsMu.Unlock()
+	delete(c.methods, name)
+}
+
+// RegisteredHandlers returns sorted names of registered handlers
+func (c *Client) RegisteredHandlers() []string {
+	c.methodsMu.RLock()
+	names := make([]string, 0, len(c.methods))
+	for name := range c.methods {
+		names = append(names, name)
+	}
+	c.methodsMu.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// handler returns registered handler by the name
+func (c *Client) handler(name string) (func(context.Context, *Trigger) error, bool) {
+	c.methodsMu.RLock()
+	defer c.methodsMu.RUnlock()
+	fn, ok := c.methods[name]
+	return fn, ok
 }
 
 // RemoveTrigger provides method for removing trigger key
 func (c *Client) RemoveTrigger(key string, t *Trigger) error {
-	encodedT, err := t.encode()
-	if err != nil {
-		return fmt.Errorf("unable to marshal trigger: %v", err)
+	if c.isClosed() {
+		return ErrClosed
 	}
-	cmd := c.c.SRem(key, encodedT)
-	if cmd.Err() != nil {
-		return fmt.Errorf("unable to remove trigger key: %v", cmd.Err())
+	member := t.raw
+	if member == "" {
+		encodedT, err := c.encode(t)
+		if err != nil {
+			return fmt.Errorf("unable to marshal trigger: %v", err)
+		}
+		member = string(encodedT)
+	}
+	err := c.remove(key, member, t.ID)
+	if err != nil {
+		return c.closedOr(fmt.Errorf("unable to remove trigger key: %v", err))
 	}
+	c.removed(t)
 
 	return nil
 }
 
-// Start provides starting of app
+// remove provides removing of the member from the key
+// and the trigger ID from the index
+func (c *Client) remove(key, member, id string) error {
+	_, err := c.c.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.SRem(key, member)
+		if id != "" {
+			pipe.HDel(c.indexKey, id)
+		}
+		return nil
+	})
+	return err
+}
+
+// Start provides starting of app. Triggers which holds
+// in the same key are started ordered by the scheduled time.
+// With Concurrency their handlers may overlap.
+// Keys are second based, but trigger is fired only
+// after its DateTime with nanoseconds and EarliestStart are passed
 func (c *Client) Start() {
-	for {
-		err := c.getReadyTriggers()
+	atomic.StoreUint32(&c.started, 1)
+	defer close(c.done)
+
+	c.runStartOnce()
+
+	var wake <-chan *redis.Message
+	if c.adaptive {
+		ps, err := c.subscribeNotify()
 		if err != nil {
-			log.Printf("unable to get ready triggers: %v", err)
+			log.Printf("unable to subscribe to notifications, interval is used: %v", err)
+		} else {
+			defer ps.Close()
+			wake = ps.Channel()
 		}
-		time.Sleep(1 * time.Second)
 	}
-}
 
-// getReadyTriggers returns decoded ready triggers
-func (c *Client) getReadyTriggers() error {
+	for {
+		res, err := c.ProcessOnce()
+		if c.isClosed() {
+			return
+		}
+		c.setLastError(err)
+		if err != nil && c.handleError(err) {
+			return
+		}
+		if err == nil {
+			c.errLog.flush()
+		}
+		d := c.afterCycle(res)
+		if wake != nil {
+			c.waitNotify(wake)
+		} else {
+			c.sleep(d)
+		}
+	}
+}
 
-	readyKeys, err := c.getReadyKeys()
+// afterCycle provides updating of the stats and checking of the lag
+// after the check of ready triggers and returns duration until the
+// next check. Keys of triggers are got once for all of them
+func (c *Client) afterCycle(res ProcessResult) time.Duration {
+	keys, err := c.getKeys()
 	if err != nil {
-		return fmt.Errorf("unable to get ready keys: %v", err)
+		log.Printf("unable to get keys after the check: %v", err)
+		c.updateStats(res.Duration, res.Fired, nil)
+		return c.interval
 	}
+	if keys == nil {
+		keys = []string{}
+	}
+	c.updateStats(res.Duration, res.Fired, keys)
+	c.checkLag(keys)
+	return c.sleepDuration(keys)
+}
 
-	return c.checkReadyKeys(readyKeys)
+// sleepDuration returns duration until the next check of
+// ready triggers. Its shorter than the interval if the next
+// trigger of keys is scheduled earlier. Overdue triggers which
+// were not fired on this check are checked after the interval
+func (c *Client) sleepDuration(keys []string) time.Duration {
+	next, ok, err := c.nextFireTime(keys)
+	if err != nil || !ok {
+		return c.interval
+	}
+	d := next.Sub(c.now())
+	if d <= 0 || d > c.interval {
+		return c.interval
+	}
+	return d
+}
 
+// Done returns a channel which is closed when Start is stopped
+func (c *Client) Done() <-chan struct{} {
+	return c.done
 }
 
-func (c *Client) checkReadyKeys(readyKeys []string) error {
-	for _, k := range readyKeys {
-		_, err := c.getTriggers(k)
-		if err != nil {
+// handleError provides handling of the error of Start
+// and returns true if Start should be stopped
+func (c *Client) handleError(err error) bool {
+	if c.onError == nil {
+		c.errLog.log(err)
+		return false
+	}
+	return c.onError(err)
+}
+
+// checkReadyKeys provides firing of ready triggers of keys
+// by the worker pool and waiting until all handlers are finished.
+// Fired triggers of each key are removed with the one command
+// after all of them are fired
+func (c *Client) checkReadyKeys(ctx context.Context, readyKeys []string) ProcessResult {
+	r := &cycle{
+		ctx:       ctx,
+		batches:   make([]*firedBatch, len(readyKeys)),
+		fifoFired: map[string]struct{}{},
+	}
+	for i, k := range readyKeys {
+		r.batches[i] = &firedBatch{}
+		c.checkReadyKey(k, r, r.batches[i])
+	}
+	r.wg.Wait()
+
+	for i, k := range readyKeys {
+		c.removeFired(k, r.batches[i])
+	}
+	return r.res
+}
+
+// checkReadyKey provides dispatching of ready triggers of the key
+// to the worker pool in order of their scheduled time
+func (c *Client) checkReadyKey(key string, r *cycle, batch *firedBatch) {
+	if r.limited {
+		return
+	}
+	ts, err := c.getTriggers(key)
+	if err != nil {
+		return
+	}
+	ts.sort()
+
+	now := c.now()
+	for _, t := range ts {
+		if r.limited || t.scheduledTime().After(now) {
+			break
+		}
+		if !c.acceptNamespace(t.Namespace) {
+			continue
+		}
+		if t.expired(now) {
+			c.skip(key, t, ReasonExpired, false)
+			r.skipped()
+			continue
+		}
+		if t.stale(now) {
+			c.skip(key, t, ReasonStale, c.dropStale)
+			r.skipped()
 			continue
 		}
+		if t.Overlap != OverlapConcurrent && c.isRunning(t.ID) {
+			continue
+		}
+		if !c.fifoReady(t, r) || !c.reserve(t) {
+			continue
+		}
+		if !c.allowFire() {
+			c.unreserve(t)
+			r.limited = true
+			break
+		}
+		c.dispatch(key, t, r, batch)
 	}
-	return nil
+}
+
+// fire provides calling of the trigger handler and adding
+// of the trigger to the batch which is removed from the key
+// after triggers are fired. Triggers without registered handler
+// are left in the key. It returns true and error of the handler
+// if handler was called
+func (c *Client) fire(ctx context.Context, key string, t *Trigger, batch *firedBatch) (bool, error) {
+	fn, ok := c.handler(t.Handler)
+	if !ok {
+		log.Printf("handler %q is not registered", t.Handler)
+		return false, nil
+	}
+	next, ok := c.nextFunc(t)
+	if !ok {
+		log.Printf("next function %q is not registered", t.Next)
+		return false, nil
+	}
+
+	if !c.recordFiring(t) {
+		log.Printf("trigger %s is already fired, its not fired again", t.ID)
+		if next != nil {
+			c.recur(key, t, next)
+		} else {
+			batch.add(t, nil)
+		}
+		return false, nil
+	}
+
+	// next occurrence of concurrent trigger
+	// is scheduled before the handler is run
+	concurrent := next != nil && t.Overlap == OverlapConcurrent && !c.noDelete
+	if concurrent {
+		c.recur(key, t, next)
+	}
+
+	firedAt := c.now()
+	hCtx, done := c.startRunning(contextWithMeta(ctx, t.Meta), t.ID)
+	err := fn(hCtx, t)
+	done()
+	c.logFired(t, firedAt, err)
+	labels := map[string]string{"namespace": t.Namespace, "handler": t.Handler, "status": handlerStatus(err)}
+	c.metrics.IncCounter(MetricTriggersFired, labels)
+	c.metrics.ObserveDuration(MetricHandlerDuration, c.now().Sub(firedAt), labels)
+	atomic.AddUint64(&c.firedCount, 1)
+	if err != nil {
+		atomic.AddUint64(&c.errorCount, 1)
+		log.Printf("handler %q is failed: %v", t.Handler, err)
+	}
+	if c.noDelete {
+		return true, err
+	}
+
+	switch {
+	case concurrent:
+		c.sendResult(t.raw, err)
+	case err != nil && t.Attempt+1 < t.maxAttempts(c.retries):
+		c.retry(key, t)
+	case err != nil && t.maxAttempts(c.retries) > 1:
+		c.moveToDead(key, t, ReasonFailed)
+		c.sendResult(t.raw, err)
+	case next != nil:
+		c.recur(key, t, next)
+		c.sendResult(t.raw, err)
+	default:
+		// dependents are released by removeFired
+		// after the trigger is removed
+		batch.add(t, err)
+	}
+	if err == nil && next != nil {
+		c.releaseDependents(t.ID)
+	}
+	c.publish(t)
+	c.archiveFired(t)
+	return true, err
+}
+
+// acceptNamespace returns true if triggers of the namespace
+// should be fired by this client
+func (c *Client) acceptNamespace(ns string) bool {
+	if _, ok := c.excludeNamespaces[ns]; ok {
+		return false
+	}
+	if len(c.includeNamespaces) == 0 {
+		return true
+	}
+	_, ok := c.includeNamespaces[ns]
+	return ok
+}
+
+func namespacesSet(namespaces []string) map[string]struct{} {
+	r := make(map[string]struct{}, len(namespaces))
+	for _, ns := range namespaces {
+		r[ns] = struct{}{}
+	}
+	return r
+}
+
+// sort provides ordering of triggers by the scheduled time
+// (including nanoseconds). Triggers with the same time are
+// ordered by the encoded form. Triggers of the one key are
+// fired in this order
+func (ts Triggers) sort() {
+	sort.SliceStable(ts, func(i, j int) bool {
+		ti, tj := ts[i].scheduledTime(), ts[j].scheduledTime()
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return ts[i].raw < ts[j].raw
+	})
 }
 
 // updateTrigger provides removing old trigger and creating a new trigger
@@ -148,12 +1332,12 @@ func (c *Client) updateTrigger(key string, t *Trigger) error {
 // getReadyKeys returns ready keys based on pattern and time
 func (c *Client) getReadyKeys() ([]string, error) {
 
-	cmd := c.c.Keys(c.pattern)
-	if cmd.Err() != nil {
-		return nil, fmt.Errorf("unable to get keys: %v", cmd.Err())
+	keys, err := c.getKeys()
+	if err != nil {
+		return nil, err
 	}
 
-	fk, err := filterTimestamps(c.pattern, cmd.Val())
+	fk, err := c.filterTimestamps(keys)
 	if err != nil {
 		return nil, err
 	}
@@ -161,23 +1345,78 @@ func (c *Client) getReadyKeys() ([]string, error) {
 	return fk, nil
 }
 
-func filterTimestamps(pattern string, ts []string) ([]string, error) {
+// getKeys returns all keys of triggers based on pattern.
+// Keys which can't be parsed are skipped
+func (c *Client) getKeys() ([]string, error) {
+
+	cmd := c.c.Keys(c.keyMatch)
+	if cmd.Err() != nil {
+		return nil, fmt.Errorf("unable to get keys: %v", cmd.Err())
+	}
+
+	return c.triggerKeys(cmd.Val()), nil
+}
+
+// defaultKeyFunc returns a function which makes keys
+// of triggers as pattern-timestamp
+func defaultKeyFunc(pattern, sep string) func(*Trigger) string {
+	return func(t *Trigger) string {
+		return pattern + sep + getUnixTimeString(t.scheduledTime())
+	}
+}
+
+// namespaceKeyFunc returns a function which makes keys
+// with the namespace of the trigger
+func namespaceKeyFunc(pattern, sep string) func(*Trigger) string {
+	return func(t *Trigger) string {
+		return pattern + sep + t.Namespace + sep + getUnixTimeString(t.scheduledTime())
+	}
+}
+
+// defaultParseKey returns a function which parses
+// keys made by defaultKeyFunc or namespaceKeyFunc
+// by the part after the last separator
+func defaultParseKey(pattern, sep string) func(string) (time.Time, error) {
+	keyPrefix := pattern + sep
+	return func(k string) (time.Time, error) {
+		if !strings.HasPrefix(k, keyPrefix) {
+			return time.Time{}, fmt.Errorf("key %q doesn't start with %q", k, keyPrefix)
+		}
+		k = strings.TrimPrefix(k, keyPrefix)
+		i, err := strconv.ParseInt(k[strings.LastIndex(k, sep)+len(sep):], base10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unable to parse time of the key: %v", err)
+		}
+		return time.Unix(i, 0), nil
+	}
+}
+
+// filterTimestamps returns keys with passed timestamps
+// sorted by the timestamp from the oldest one.
+// Keys which can't be parsed are skipped
+func (c *Client) filterTimestamps(ts []string) ([]string, error) {
 	var r []string
+	timestamps := map[string]int64{}
 
-	ct := time.Now().UTC().Unix()
+	ct := c.now().Unix()
 
 	for _, k := range ts {
-		slots := strings.Split(k, fmt.Sprintf("%s-", pattern))
-		i, err := strconv.ParseInt(slots[1], base10, 64)
-		if err != nil {
-			return nil, err
+		kt, ok := c.keyTime(k)
+		if !ok {
+			continue
 		}
 
+		i := kt.Unix()
 		if i <= ct {
 			r = append(r, k)
+			timestamps[k] = i
 		}
 	}
 
+	sort.Slice(r, func(i, j int) bool {
+		return timestamps[r[i]] < timestamps[r[j]]
+	})
+
 	return r, nil
 
 }
@@ -210,11 +1449,31 @@ func (c *Client) decode(s string) (*Trigger, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to unmarshal: %v", err)
 	}
+	if err = c.openPayload(t); err != nil {
+		return nil, err
+	}
+	t.raw = s
 
 	return t, nil
 
 }
 
+// jitterOffset returns random offset from -jitter to jitter
+func (c *Client) jitterOffset() time.Duration {
+	c.jitterMu.Lock()
+	defer c.jitterMu.Unlock()
+	return time.Duration(c.jitterRand.Int63n(2*int64(c.jitter)+1)) - c.jitter
+}
+
+// processSource returns <hostname>:<pid> of the process
+func processSource() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
 // getUnixTimeString provides converting of unix timestamp to string
 func getUnixTimeString(t time.Time) string {
 	return strconv.FormatInt(t.Unix(), base10)
@@ -0,0 +1,194 @@
+package rc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+)
+
+// newTestClient returns client of the miniredis server
+// which is closed when test is finished
+func newTestClient(t *testing.T, opts ...Option) (*Client, *miniredis.Miniredis) {
+	t.Helper()
+	m := miniredis.RunT(t)
+	c, err := newClient(&ClientOptions{Options: redis.Options{Addr: m.Addr()}}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close(context.Background()) })
+	return c, m
+}
+
+// testClock is a Clock of tests which is moved by add
+type testClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (k *testClock) now() time.Time {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.t
+}
+
+func (k *testClock) add(d time.Duration) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.t = k.t.Add(d)
+}
+
+func TestScheduleJitterSkipsRecurring(t *testing.T) {
+	c, _ := newTestClient(t, func(o *ClientOptions) {
+		o.ScheduleJitter = time.Hour
+	})
+	at := time.Now().Add(time.Hour).Truncate(time.Minute)
+	tr := &Trigger{Handler: "h", DateTime: at, CronSpec: "* * * * *"}
+	if err := c.AddTrigger(tr); err != nil {
+		t.Fatal(err)
+	}
+	if !tr.DateTime.Equal(at) {
+		t.Fatalf("expected recurring trigger at %v, got %v", at, tr.DateTime)
+	}
+}
+
+func TestEncodeOmitsFunc(t *testing.T) {
+	tr := &Trigger{ID: "a", Handler: "h", DateTime: time.Now(), Func: func() {}}
+	b, err := tr.encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(b, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"Func", "func"} {
+		if _, ok := fields[k]; ok {
+			t.Fatalf("expected encoded trigger without %s: %s", k, b)
+		}
+	}
+	if _, ok := fields["id"]; !ok {
+		t.Fatalf("expected encoded trigger with id: %s", b)
+	}
+}
+
+func TestFilterTimestampsSortsBuckets(t *testing.T) {
+	c, _ := newTestClient(t)
+	now := time.Now()
+	key := func(d time.Duration) string {
+		return c.keyFunc(&Trigger{DateTime: now.Add(d)})
+	}
+
+	// keys are discovered out of order
+	keys := []string{key(-time.Second), key(time.Hour), key(-3 * time.Hour), key(-time.Minute)}
+	got, err := c.filterTimestamps(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{key(-3 * time.Hour), key(-time.Minute), key(-time.Second)}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestOldestBucketsAreFiredFirst(t *testing.T) {
+	c, _ := newTestClient(t)
+	var fired []string
+	c.RegisterTriggerHandler("h", func(t *Trigger) error {
+		fired = append(fired, t.ID)
+		return nil
+	})
+
+	// triggers are added out of order
+	now := time.Now()
+	for _, tr := range []*Trigger{
+		{ID: "c", Handler: "h", DateTime: now.Add(-time.Second)},
+		{ID: "a", Handler: "h", DateTime: now.Add(-100 * time.Second)},
+		{ID: "b", Handler: "h", DateTime: now.Add(-10 * time.Second)},
+	} {
+		if err := c.AddTrigger(tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := c.ProcessOnce(); err != nil {
+		t.Fatal(err)
+	}
+	if len(fired) != 3 || fired[0] != "a" || fired[1] != "b" || fired[2] != "c" {
+		t.Fatalf("expected triggers to be fired oldest first, got %v", fired)
+	}
+}
+
+func TestVerifyWrites(t *testing.T) {
+	for _, verify := range []bool{false, true} {
+		c, _ := newTestClient(t, func(o *ClientOptions) {
+			o.VerifyWrites = verify
+		})
+		now := time.Now()
+		if err := c.AddTrigger(&Trigger{Handler: "h", DateTime: now}); err != nil {
+			t.Fatal(err)
+		}
+
+		// write is lost, since the key is removed by Redis
+		// at once because of the passed ExpireAt
+		err := c.AddTrigger(&Trigger{Handler: "h", DateTime: now, ExpireAt: now.Add(-time.Second)})
+		if verify && err != ErrWriteLost {
+			t.Fatalf("expected ErrWriteLost, got %v", err)
+		}
+		if !verify && err != nil {
+			t.Fatalf("expected no error without VerifyWrites, got %v", err)
+		}
+	}
+}
+
+func TestRejectPast(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	for _, reject := range []bool{false, true} {
+		c, _ := newTestClient(t, func(o *ClientOptions) {
+			o.RejectPast = reject
+		})
+		fired := 0
+		c.RegisterHandler("h", func() { fired++ })
+
+		err := c.AddTrigger(&Trigger{Handler: "h", DateTime: past})
+		if reject {
+			if err != ErrPast {
+				t.Fatalf("expected ErrPast, got %v", err)
+			}
+			if err = c.AddTrigger(&Trigger{Handler: "h", DateTime: time.Now().Add(time.Hour)}); err != nil {
+				t.Fatalf("expected future trigger to be added, got %v", err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = c.ProcessOnce(); err != nil {
+			t.Fatal(err)
+		}
+		if fired != 1 {
+			t.Fatalf("expected passed trigger to be fired on the next check, got %d", fired)
+		}
+	}
+}
+
+func TestInvalidOptionsAreCheckedBeforeConnecting(t *testing.T) {
+	_, err := newClient(&ClientOptions{
+		Options:      redis.Options{Addr: "127.0.0.1:1"},
+		KeySeparator: ":",
+	})
+	if err == nil || !strings.Contains(err.Error(), "KeySeparator") {
+		t.Fatalf("expected error of KeySeparator without ping, got %v", err)
+	}
+}
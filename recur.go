@@ -0,0 +1,130 @@
+package rc
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// OverlapQueue is a policy of the recurring trigger which next
+	// occurrence is scheduled after the handler is finished. Occurrences
+	// which are passed while handler is running are fired back-to-back
+	OverlapQueue = "queue"
+	// OverlapSkip is a policy of the recurring trigger which next
+	// occurrence is scheduled after the handler is finished. Occurrences
+	// which are passed while handler is running are skipped
+	OverlapSkip = "skip"
+	// OverlapConcurrent is a policy of the recurring trigger which next
+	// occurrence is scheduled before the handler is run, so it can
+	// be fired by other clients while the previous one is running.
+	// Check of ready triggers by Start waits for the handlers which
+	// it has run, so occurrences of the trigger don't overlap within
+	// one Start loop. Failed handler of such trigger is not retried
+	OverlapConcurrent = "concurrent"
+)
+
+// RegisterNextFunc provides registration of the function by the name
+// which returns time of the next occurrence of recurring triggers
+// after the prev one. Triggers reference it with Next. If function
+// returns zero time or time which is not after prev, recurrence is stopped
+func (c *Client) RegisterNextFunc(name string, fn func(prev time.Time) time.Time) {
+	c.methodsMu.Lock()
+	defer c.methodsMu.Unlock()
+	c.nextFuncs[name] = fn
+}
+
+// nextFunc returns next function of the trigger by its CronSpec
+// or registered next function by its Next.
+// It returns nil and true for not recurring trigger
+func (c *Client) nextFunc(t *Trigger) (func(time.Time) time.Time, bool) {
+	if t.CronSpec != "" {
+		next, err := c.cronNext(t)
+		if err != nil {
+			log.Printf("unable to parse cron spec: %v", err)
+			return nil, true
+		}
+		return next, true
+	}
+	if t.Next == "" {
+		return nil, true
+	}
+
+	c.methodsMu.RLock()
+	defer c.methodsMu.RUnlock()
+	fn, ok := c.nextFuncs[t.Next]
+	return fn, ok
+}
+
+// recur provides scheduling of the next occurrence of the fired
+// recurring trigger instead of its removing. The next occurrence
+// keeps the ID of the trigger
+func (c *Client) recur(key string, t *Trigger, next func(time.Time) time.Time) {
+
+	nt := *t
+	nt.DateTime = next(t.DateTime)
+	nt.EarliestStart = time.Time{}
+	nt.Attempt = 0
+	nt.NextAttempt = time.Time{}
+	if t.Overlap == OverlapSkip {
+		now := c.now()
+		for prev := nt.DateTime; !prev.IsZero() && !prev.After(now); prev = nt.DateTime {
+			if nt.DateTime = next(prev); !nt.DateTime.After(prev) {
+				nt.DateTime = time.Time{}
+				break
+			}
+		}
+	}
+	if nt.DateTime.IsZero() || !nt.DateTime.After(t.DateTime) {
+		if err := c.remove(key, t.raw, t.ID); err != nil {
+			log.Printf("unable to remove fired trigger: %v", err)
+		}
+		return
+	}
+
+	err := c.rescheduleWithRetries(key, t, &nt)
+	if err == nil {
+		return
+	}
+	if c.rescheduleDeadLetter {
+		c.moveToDead(key, t, ReasonRescheduleFailed)
+	}
+
+	rErr := &RescheduleError{ID: t.ID, Err: err}
+	if c.onError == nil {
+		log.Print(rErr)
+		return
+	}
+	c.onError(rErr)
+}
+
+// rescheduleWithRetries provides scheduling of the next occurrence
+// with RescheduleRetries retries and exponential backoff
+func (c *Client) rescheduleWithRetries(key string, t, next *Trigger) error {
+
+	delay := c.rescheduleDelay
+	var err error
+	for i := 0; i <= c.rescheduleRetries; i++ {
+		if i > 0 {
+			c.sleep(delay)
+			delay *= 2
+		}
+		if _, err = c.reschedule(key, t, next); err == nil || c.isClosed() {
+			return err
+		}
+	}
+
+	return err
+}
+
+// RescheduleError is passed to ErrorHandler when the handler
+// of the recurring trigger succeeds, but its next occurrence
+// can't be scheduled
+type RescheduleError struct {
+	ID  string
+	Err error
+}
+
+func (e *RescheduleError) Error() string {
+	return fmt.Sprintf("unable to schedule next occurrence of the trigger %s: %v", e.ID, e.Err)
+}
@@ -0,0 +1,230 @@
+package rc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+)
+
+// newOverlapClient returns client with the test clock
+// and the next function "minute"
+func newOverlapClient(t *testing.T, m *miniredis.Miniredis, clock *testClock) *Client {
+	t.Helper()
+	c, err := newClient(&ClientOptions{
+		Options: redis.Options{Addr: m.Addr()},
+		Clock:   clock.now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close(context.Background()) })
+	c.RegisterNextFunc("minute", func(prev time.Time) time.Time {
+		return prev.Add(time.Minute)
+	})
+	return c
+}
+
+func TestOverlapQueue(t *testing.T) {
+	clock := &testClock{t: time.Now().Truncate(time.Second)}
+	start := clock.now()
+	c := newOverlapClient(t, miniredis.RunT(t), clock)
+	c.RegisterHandler("slow", func() { clock.add(3 * time.Minute) })
+
+	if err := c.AddTrigger(&Trigger{ID: "a", Handler: "slow", DateTime: start, Next: "minute", Overlap: OverlapQueue}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ProcessOnce(); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := c.Get("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := start.Add(time.Minute); !tr.DateTime.Equal(want) {
+		t.Fatalf("expected passed occurrence %v to be queued, got %v", want, tr.DateTime)
+	}
+
+	res, err := c.ProcessOnce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Fired != 1 {
+		t.Fatalf("expected queued occurrence to be fired, got %d", res.Fired)
+	}
+}
+
+func TestOverlapSkip(t *testing.T) {
+	clock := &testClock{t: time.Now().Truncate(time.Second)}
+	start := clock.now()
+	c := newOverlapClient(t, miniredis.RunT(t), clock)
+	c.RegisterHandler("slow", func() { clock.add(3 * time.Minute) })
+
+	if err := c.AddTrigger(&Trigger{ID: "a", Handler: "slow", DateTime: start, Next: "minute", Overlap: OverlapSkip}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ProcessOnce(); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := c.Get("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := start.Add(4 * time.Minute); !tr.DateTime.Equal(want) {
+		t.Fatalf("expected passed occurrences to be skipped up to %v, got %v", want, tr.DateTime)
+	}
+}
+
+func TestOverlapConcurrent(t *testing.T) {
+	m := miniredis.RunT(t)
+	clock := &testClock{t: time.Now().Truncate(time.Second)}
+	start := clock.now()
+	clock.add(time.Minute)
+
+	var (
+		mu        sync.Mutex
+		active    int
+		maxActive int
+		calls     int
+	)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := func() {
+		mu.Lock()
+		active++
+		calls++
+		if active > maxActive {
+			maxActive = active
+		}
+		first := calls == 1
+		mu.Unlock()
+		if first {
+			close(started)
+			<-release
+		}
+		mu.Lock()
+		active--
+		mu.Unlock()
+	}
+
+	a := newOverlapClient(t, m, clock)
+	b := newOverlapClient(t, m, clock)
+	a.RegisterHandler("slow", handler)
+	b.RegisterHandler("slow", handler)
+
+	if err := a.AddTrigger(&Trigger{ID: "a", Handler: "slow", DateTime: start, Next: "minute", Overlap: OverlapConcurrent}); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := a.ProcessOnce(); err != nil {
+			t.Error(err)
+		}
+	}()
+	<-started
+
+	res, err := b.ProcessOnce()
+	close(release)
+	<-done
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Fired != 1 {
+		t.Fatalf("expected next occurrence to be fired while previous one is running, got %d", res.Fired)
+	}
+	if maxActive != 2 {
+		t.Fatalf("expected 2 overlapping runs, got %d", maxActive)
+	}
+}
+
+func TestRunningTriggerIsNotFiredAgain(t *testing.T) {
+	clock := &testClock{t: time.Now().Truncate(time.Second)}
+	c := newOverlapClient(t, miniredis.RunT(t), clock)
+
+	var once sync.Once
+	started := make(chan struct{})
+	release := make(chan struct{})
+	c.RegisterHandler("slow", func() {
+		first := false
+		once.Do(func() { first = true })
+		if first {
+			close(started)
+			<-release
+		}
+	})
+	if err := c.AddTrigger(&Trigger{ID: "a", Handler: "slow", DateTime: clock.now(), Next: "minute"}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := c.ProcessOnce(); err != nil {
+			t.Error(err)
+		}
+	}()
+	<-started
+
+	res, err := c.ProcessOnce()
+	close(release)
+	<-done
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Fired != 0 {
+		t.Fatalf("expected running trigger not to be fired again, got %d", res.Fired)
+	}
+}
+
+func TestRescheduleFailure(t *testing.T) {
+	for _, deadLetter := range []bool{false, true} {
+		var rErrs []*RescheduleError
+		c, m := newTestClient(t, func(o *ClientOptions) {
+			o.RescheduleRetries = 2
+			o.RescheduleDelay = time.Millisecond
+			o.RescheduleDeadLetter = deadLetter
+			o.ErrorHandler = func(err error) bool {
+				if rErr, ok := err.(*RescheduleError); ok {
+					rErrs = append(rErrs, rErr)
+				}
+				return false
+			}
+		})
+		c.RegisterHandler("h", func() {})
+		c.RegisterNextFunc("hour", func(prev time.Time) time.Time { return prev.Add(time.Hour) })
+
+		at := time.Now().Add(-time.Second)
+		if err := c.AddTrigger(&Trigger{ID: "a", Handler: "h", DateTime: at, Next: "hour"}); err != nil {
+			t.Fatal(err)
+		}
+		// the key of the next occurrence can't be written
+		if err := m.Set(c.keyFunc(&Trigger{DateTime: at.Add(time.Hour)}), "x"); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := c.ProcessOnce()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Fired != 1 {
+			t.Fatalf("expected handler to be called, got %d", res.Fired)
+		}
+		if len(rErrs) != 1 || rErrs[0].ID != "a" {
+			t.Fatalf("expected RescheduleError of the trigger, got %v", rErrs)
+		}
+		dead, err := c.ListDead()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deadLetter && (len(dead) != 1 || dead[0].Reason != ReasonRescheduleFailed) {
+			t.Fatalf("expected trigger in the dead-letter set, got %v", dead)
+		}
+		if !deadLetter && len(dead) != 0 {
+			t.Fatalf("expected no dead triggers, got %v", dead)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package rc
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// RequeueKey provides scheduling again of all triggers from the key.
+// Key can be a key of triggers or the dead-letter set <pattern>:dead.
+// Triggers which time is passed are scheduled for immediate firing,
+// others are scheduled back to the keys of their time. Attempts
+// of triggers and passed deadlines are reset. Members which can't
+// be decoded are skipped and kept in the key. With RejectPast
+// triggers which time is passed are rejected by ErrPast.
+// It returns number of requeued triggers
+func (c *Client) RequeueKey(key string) (int, error) {
+
+	if c.isClosed() {
+		return 0, ErrClosed
+	}
+
+	members, err := c.c.SMembers(key).Result()
+	if err != nil {
+		return 0, c.closedOr(fmt.Errorf("unable to get triggers by the key %s: %v", key, err))
+	}
+
+	var requeued int
+	now := c.now()
+	for _, m := range members {
+		t := c.decodeRequeued(key, m)
+		if t == nil {
+			continue
+		}
+		t.Attempt = 0
+		t.NextAttempt = time.Time{}
+		if !t.Deadline.IsZero() && !t.Deadline.After(now) {
+			t.Deadline = time.Time{}
+		}
+		if t.scheduledTime().Before(now) {
+			t.DateTime = now
+			t.EarliestStart = time.Time{}
+		}
+
+		if err = c.c.SRem(key, m).Err(); err != nil {
+			return requeued, c.closedOr(fmt.Errorf("unable to remove trigger from the key %s: %v", key, err))
+		}
+		if err = c.AddTrigger(t); err != nil {
+			if rErr := c.c.SAdd(key, m).Err(); rErr != nil {
+				log.Printf("unable to restore trigger %s in the key %s: %v", t.ID, key, rErr)
+			}
+			return requeued, err
+		}
+		requeued++
+	}
+
+	return requeued, nil
+}
+
+// decodeRequeued returns trigger of the member of the key
+// or nil if member can't be decoded
+func (c *Client) decodeRequeued(key, member string) *Trigger {
+	if key != c.deadKey {
+		t, err := c.decode(member)
+		if err != nil || t.ID == "" {
+			return nil
+		}
+		return t
+	}
+
+	d := &DeadTrigger{}
+	if err := json.Unmarshal([]byte(member), d); err != nil || d.Trigger == nil {
+		return nil
+	}
+	if err := c.openPayload(d.Trigger); err != nil {
+		return nil
+	}
+	return d.Trigger
+}
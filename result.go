@@ -0,0 +1,74 @@
+package rc
+
+// AddTriggerWithResult provides inserting of the new trigger
+// and returns a channel which receives an error of the handler
+// (or nil) when trigger is fired by this client.
+// If trigger is fired by another process, channel is never resolved
+func (c *Client) AddTriggerWithResult(t *Trigger) (<-chan error, error) {
+
+	encodedT, err := c.encodeTrigger(t)
+	if err != nil {
+		return nil, err
+	}
+
+	member := string(encodedT)
+	result := make(chan error, 1)
+
+	c.resultsMu.Lock()
+	c.results[member] = append(c.results[member], result)
+	c.resultsMu.Unlock()
+
+	if _, err = c.add(t, encodedT); err != nil {
+		c.removeResult(member, result)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// sendResult provides sending of the handler error to results
+// which are waiting for the trigger member
+func (c *Client) sendResult(member string, err error) {
+
+	c.resultsMu.Lock()
+	results := c.results[member]
+	delete(c.results, member)
+	c.resultsMu.Unlock()
+
+	for _, r := range results {
+		r <- err
+		close(r)
+	}
+}
+
+// moveResults provides moving of results which are waiting
+// for the trigger member to the new member of the same trigger
+func (c *Client) moveResults(member, newMember string) {
+
+	c.resultsMu.Lock()
+	defer c.resultsMu.Unlock()
+
+	if results, ok := c.results[member]; ok {
+		delete(c.results, member)
+		c.results[newMember] = append(c.results[newMember], results...)
+	}
+}
+
+func (c *Client) removeResult(member string, result chan error) {
+
+	c.resultsMu.Lock()
+	defer c.resultsMu.Unlock()
+
+	results := c.results[member]
+	for i, r := range results {
+		if r == result {
+			results = append(results[:i], results[i+1:]...)
+			break
+		}
+	}
+	if len(results) == 0 {
+		delete(c.results, member)
+		return
+	}
+	c.results[member] = results
+}
@@ -0,0 +1,62 @@
+package rc
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/go-redis/redis"
+)
+
+// ListRetrying returns pending triggers which
+// are waiting for the retry after failure
+func (c *Client) ListRetrying() (Triggers, error) {
+	return c.listWhere(func(t *Trigger) bool {
+		return t.Attempt > 0
+	})
+}
+
+// retry provides moving of the failed trigger from the key
+// to the key of its next attempt
+func (c *Client) retry(key string, t *Trigger) {
+
+	next := *t
+	next.Attempt++
+	next.NextAttempt = c.now().Add(c.retryDelay)
+
+	encodedT, err := c.reschedule(key, t, &next)
+	if err != nil {
+		log.Printf("unable to retry trigger: %v", err)
+		return
+	}
+	c.metrics.IncCounter(MetricTriggersRetried, map[string]string{"namespace": t.Namespace, "handler": t.Handler})
+	c.moveResults(t.raw, string(encodedT))
+}
+
+// reschedule provides atomic replacing of the trigger
+// stored in the key by the next trigger and returns
+// the encoded next trigger
+func (c *Client) reschedule(key string, t, next *Trigger) ([]byte, error) {
+
+	encodedT, err := c.encode(next)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal trigger: %v", err)
+	}
+
+	nextKey, err := c.storeKey(next)
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.c.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.SRem(key, t.raw)
+		pipe.SAdd(nextKey, encodedT)
+		c.expire(pipe, nextKey, next)
+		pipe.HSet(c.indexKey, next.ID, nextKey)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.notify(next)
+
+	return encodedT, nil
+}
@@ -0,0 +1,66 @@
+package rc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// selfTestHandler is a name of the handler of triggers of SelfTest
+const selfTestHandler = "rc-selftest"
+
+// SelfTest provides checking of adding, firing and removing of triggers
+// with the Redis of the client. It adds the trigger which is scheduled
+// a second in the future and waits until its fired by Start of this
+// client and removed, or until the context is done. Trigger of self
+// test has no namespace, so it's not fired if IncludeNamespaces
+// doesn't contain the empty namespace
+func (c *Client) SelfTest(ctx context.Context) error {
+
+	if atomic.LoadUint32(&c.started) == 0 {
+		return errors.New("self test is failed: Start is not running")
+	}
+
+	token, err := json.Marshal(c.newID())
+	if err != nil {
+		return fmt.Errorf("self test is failed: unable to marshal payload: %v", err)
+	}
+	c.RegisterTriggerHandler(selfTestHandler, func(t *Trigger) error {
+		if !bytes.Equal(t.Payload, token) {
+			return fmt.Errorf("payload %s is decoded instead of %s", t.Payload, token)
+		}
+		return nil
+	})
+
+	t := &Trigger{
+		DateTime: c.now().Add(time.Second),
+		Handler:  selfTestHandler,
+		Payload:  token,
+	}
+	result, err := c.AddTriggerWithResult(t)
+	if err != nil {
+		return fmt.Errorf("self test is failed: unable to add trigger: %v", err)
+	}
+
+	select {
+	case err = <-result:
+		if err != nil {
+			return fmt.Errorf("self test is failed: handler returned error: %v", err)
+		}
+	case <-ctx.Done():
+		if cErr := c.Cancel(t.ID); cErr != nil && cErr != ErrNotFound {
+			return fmt.Errorf("self test is failed: trigger is not fired: %v, unable to cancel it: %v", ctx.Err(), cErr)
+		}
+		return fmt.Errorf("self test is failed: trigger is not fired: %v", ctx.Err())
+	}
+
+	if _, err = c.Get(t.ID); err != ErrNotFound {
+		return fmt.Errorf("self test is failed: trigger is not removed after firing: %v", err)
+	}
+
+	return nil
+}
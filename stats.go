@@ -0,0 +1,143 @@
+package rc
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// Stats defines a state of the last check of ready triggers
+type Stats struct {
+	// CycleDuration is a duration of the last check
+	CycleDuration time.Duration
+	// Ready is a number of triggers fired on the last check
+	Ready int
+	// Pending is a number of triggers stored in Redis
+	// after the last check
+	Pending int64
+	// PendingDelta is a change of Pending from the previous
+	// check. Positive value means that backlog is growing
+	PendingDelta int64
+}
+
+// Stats returns a state of the last check of ready triggers
+func (c *Client) Stats() Stats {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	return c.stats
+}
+
+// FiredCount returns number of triggers fired by the client
+// since its creation, including failed ones
+func (c *Client) FiredCount() uint64 {
+	return atomic.LoadUint64(&c.firedCount)
+}
+
+// ErrorCount returns number of triggers which handlers
+// are failed since creation of the client
+func (c *Client) ErrorCount() uint64 {
+	return atomic.LoadUint64(&c.errorCount)
+}
+
+// LastError returns error of the last check of ready triggers
+// and its time. It returns nil if the last check is succeeded
+func (c *Client) LastError() (error, time.Time) {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	return c.lastErr, c.lastErrAt
+}
+
+func (c *Client) setLastError(err error) {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	c.lastErr = err
+	c.lastErrAt = time.Time{}
+	if err != nil {
+		c.lastErrAt = c.now()
+		c.metrics.IncCounter(MetricCycleErrors, nil)
+	}
+}
+
+// updateStats provides updating of the stats after
+// the check of ready triggers. Pending is counted by keys,
+// its kept from the previous check if keys are nil
+func (c *Client) updateStats(d time.Duration, ready int, keys []string) {
+
+	c.statsMu.Lock()
+	pending := c.stats.Pending
+	c.statsMu.Unlock()
+
+	if keys != nil {
+		if p, err := c.countTriggers(keys); err == nil {
+			pending = p
+		}
+	}
+
+	c.statsMu.Lock()
+	c.stats = Stats{
+		CycleDuration: d,
+		Ready:         ready,
+		Pending:       pending,
+		PendingDelta:  pending - c.stats.Pending,
+	}
+	c.statsMu.Unlock()
+
+	c.metrics.ObserveDuration(MetricCycleDuration, d, nil)
+	if c.onCycle != nil {
+		c.onCycle(d, ready, pending)
+	}
+}
+
+// checkBacklog returns ErrBacklogFull if number
+// of pending triggers exceeds MaxPending
+func (c *Client) checkBacklog() error {
+	if c.maxPending <= 0 {
+		return nil
+	}
+
+	pending := c.Stats().Pending
+	if c.livePending {
+		var err error
+		if pending, err = c.countPending(); err != nil {
+			return fmt.Errorf("unable to count pending triggers: %v", err)
+		}
+	}
+	if pending >= c.maxPending {
+		return ErrBacklogFull
+	}
+
+	return nil
+}
+
+// countPending returns number of triggers stored in Redis
+func (c *Client) countPending() (int64, error) {
+
+	keys, err := c.getKeys()
+	if err != nil {
+		return 0, err
+	}
+
+	return c.countTriggers(keys)
+}
+
+// countTriggers returns number of triggers stored in keys
+func (c *Client) countTriggers(keys []string) (int64, error) {
+
+	pipe := c.c.Pipeline()
+	for _, k := range keys {
+		pipe.SCard(k)
+	}
+	cmds, err := pipe.Exec()
+	if err != nil {
+		return 0, fmt.Errorf("unable to count triggers: %v", err)
+	}
+
+	var pending int64
+	for _, cmd := range cmds {
+		pending += cmd.(*redis.IntCmd).Val()
+	}
+
+	return pending, nil
+}
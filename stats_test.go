@@ -0,0 +1,68 @@
+package rc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAfterCycle(t *testing.T) {
+	clock := &testClock{t: time.Now().Truncate(time.Second)}
+	var overdue int64
+	c, m := newTestClient(t, func(o *ClientOptions) {
+		o.Clock = clock.now
+		o.Interval = time.Hour
+		o.OnLag = func(n int64) { overdue = n }
+		o.LagThreshold = 1
+	})
+	if err := m.Set("rc-config", "value"); err != nil {
+		t.Fatal(err)
+	}
+	for i, d := range []time.Duration{-2 * time.Minute, -time.Minute, 10 * time.Minute} {
+		tr := &Trigger{ID: string(rune('a' + i)), Handler: "h", DateTime: clock.now().Add(d)}
+		if err := c.AddTrigger(tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := c.afterCycle(ProcessResult{Fired: 1})
+	if d != time.Hour {
+		t.Fatalf("expected interval with overdue triggers, got %v", d)
+	}
+	if s := c.Stats(); s.Pending != 3 || s.Ready != 1 {
+		t.Fatalf("expected 3 pending and 1 ready, got %+v", s)
+	}
+	if overdue != 2 {
+		t.Fatalf("expected OnLag with 2 overdue triggers, got %d", overdue)
+	}
+
+	if d = c.sleepDuration([]string{c.keyFunc(&Trigger{DateTime: clock.now().Add(10 * time.Minute)})}); d != 10*time.Minute {
+		t.Fatalf("expected sleep until the next trigger, got %v", d)
+	}
+
+	c.updateStats(0, 0, nil)
+	if s := c.Stats(); s.Pending != 3 || s.PendingDelta != 0 {
+		t.Fatalf("expected pending kept without keys, got %+v", s)
+	}
+}
+
+func TestBacklogWithForeignKey(t *testing.T) {
+	c, m := newTestClient(t, func(o *ClientOptions) {
+		o.MaxPending = 2
+		o.LivePending = true
+	})
+	if err := m.Set("rc-config", "value"); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now().Add(time.Hour)
+	for _, id := range []string{"a", "b"} {
+		if err := c.AddTrigger(&Trigger{ID: id, Handler: "h", DateTime: at}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.AddTrigger(&Trigger{ID: "c", Handler: "h", DateTime: at}); err != ErrBacklogFull {
+		t.Fatalf("expected ErrBacklogFull, got %v", err)
+	}
+	if n, err := c.countPending(); err != nil || n != 2 {
+		t.Fatalf("expected 2 pending triggers, got %d %v", n, err)
+	}
+}
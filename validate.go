@@ -0,0 +1,32 @@
+package rc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RegisterValidator provides registration of the validation function
+// of payloads of triggers with the handler. Payload is validated by
+// AddTrigger and another methods of adding, so trigger with invalid
+// payload is rejected instead of failing when its fired
+func (c *Client) RegisterValidator(handler string, fn func(payload json.RawMessage) error) {
+	c.methodsMu.Lock()
+	defer c.methodsMu.Unlock()
+	c.validators[handler] = fn
+}
+
+// validate returns an error if payload of the trigger
+// is rejected by the validator of its handler
+func (c *Client) validate(t *Trigger) error {
+	c.methodsMu.RLock()
+	fn, ok := c.validators[t.Handler]
+	c.methodsMu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	if err := fn(t.Payload); err != nil {
+		return fmt.Errorf("invalid payload of the handler %q: %v", t.Handler, err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package rc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookPayload defines a payload of the trigger
+// which is fired by the webhook handler
+type WebhookPayload struct {
+	URL string `json:"url"`
+	// Method is a HTTP method of the request. By default its POST
+	Method string `json:"method,omitempty"`
+	// Body is sent as a body of the request
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// WebhookOptions defines options of the webhook handler
+type WebhookOptions struct {
+	// Timeout is a timeout of the one request.
+	// By default its 10 seconds
+	Timeout time.Duration
+	// Retries is a number of retries of the failed request
+	Retries int
+	// RetryDelay is a duration between retries
+	RetryDelay time.Duration
+}
+
+// RegisterWebhookHandler provides registration of the handler
+// by the name which sends HTTP request described by WebhookPayload
+// in the payload of the trigger. Request is failed if response
+// status is not 2xx. Its registered by RegisterContextHandler,
+// so requests and retries are stopped when the trigger is cancelled
+func (c *Client) RegisterWebhookHandler(name string, options WebhookOptions) {
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+
+	c.RegisterContextHandler(name, func(ctx context.Context, t *Trigger) error {
+		p := &WebhookPayload{}
+		if err := json.Unmarshal(t.Payload, p); err != nil {
+			return fmt.Errorf("unable to unmarshal webhook payload: %v", err)
+		}
+
+		var err error
+		for i := 0; i <= options.Retries; i++ {
+			if i > 0 {
+				timer := time.NewTimer(options.RetryDelay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+			if err = sendWebhook(ctx, client, p); err == nil {
+				return nil
+			}
+		}
+		return err
+	})
+}
+
+func sendWebhook(ctx context.Context, client *http.Client, p *WebhookPayload) error {
+	method := p.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.URL, bytes.NewReader(p.Body))
+	if err != nil {
+		return fmt.Errorf("unable to create webhook request: %v", err)
+	}
+	if len(p.Body) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send webhook request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook request is failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
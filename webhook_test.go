@@ -0,0 +1,42 @@
+package rc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookRetriesAreCancelled(t *testing.T) {
+	requests := make(chan struct{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c, _ := newTestClient(t)
+	c.RegisterWebhookHandler("hook", WebhookOptions{Retries: 3, RetryDelay: time.Hour})
+	fn := c.methods["hook"]
+	payload, _ := json.Marshal(WebhookPayload{URL: srv.URL})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- fn(ctx, &Trigger{ID: "a", Payload: payload}) }()
+
+	<-requests
+	cancel()
+	select {
+	case err := <-errc:
+		if err != context.Canceled {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected webhook retries to be stopped by the context")
+	}
+	if n := len(requests); n != 0 {
+		t.Fatalf("expected no retries after cancel, got %d", n)
+	}
+}
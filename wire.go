@@ -0,0 +1,85 @@
+package rc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// wireTimeLayouts are accepted layouts of times without zone
+var wireTimeLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+}
+
+// wireTime defines a time which is decoded from the wire format
+type wireTime time.Time
+
+func (w *wireTime) UnmarshalJSON(b []byte) error {
+
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	if !strings.HasPrefix(s, `"`) {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid time %s", s)
+		}
+		sec, frac := math.Modf(f)
+		*w = wireTime(time.Unix(int64(sec), int64(frac*float64(time.Second))).UTC())
+		return nil
+	}
+
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		*w = wireTime(t)
+		return nil
+	}
+	for _, layout := range wireTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			*w = wireTime(t)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid time %q", s)
+}
+
+// triggerFields has fields of Trigger without its methods
+type triggerFields Trigger
+
+// UnmarshalJSON provides decoding of the trigger in the wire format
+func (t *Trigger) UnmarshalJSON(b []byte) error {
+
+	w := struct {
+		*triggerFields
+		DateTime      wireTime `json:"date_time"`
+		NextAttempt   wireTime `json:"next_attempt"`
+		EarliestStart wireTime `json:"earliest_start"`
+		Deadline      wireTime `json:"deadline"`
+		ExpireAt      wireTime `json:"expire_at"`
+	}{
+		triggerFields: (*triggerFields)(t),
+	}
+	if err := json.Unmarshal(b, &w); err != nil {
+		return err
+	}
+
+	t.DateTime = time.Time(w.DateTime)
+	t.NextAttempt = time.Time(w.NextAttempt)
+	t.EarliestStart = time.Time(w.EarliestStart)
+	t.Deadline = time.Time(w.Deadline)
+	t.ExpireAt = time.Time(w.ExpireAt)
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package rc
+
+import (
+	"testing"
+	"time"
+)
+
+// conformancePayloads are triggers written by hand
+// as other clients write them by the wire format
+var conformancePayloads = map[string]string{
+	"rc-1700000000": `{"id":"py-1","date_time":"2023-11-14 22:13:20.5","handler":"report",` +
+		`"namespace":"billing","payload":{"user":42},"labels":{"lang":"python"},"unknown":true}`,
+	"rc-1700000001": `{"id":"py-2","date_time":1700000001,"handler":"report","attempt":0,` +
+		`"next_attempt":null,"earliest_start":"","deadline":"0001-01-01T00:00:00Z","max_attempts":1}`,
+}
+
+func TestWireFormatConformance(t *testing.T) {
+	c, m := newTestClient(t)
+
+	fired := map[string]*Trigger{}
+	c.RegisterTriggerHandler("report", func(t *Trigger) error {
+		fired[t.ID] = t
+		return nil
+	})
+	for key, member := range conformancePayloads {
+		if _, err := m.SAdd(key, member); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m.HSet("rc:ids", "py-1", "rc-1700000000")
+	m.HSet("rc:ids", "py-2", "rc-1700000001")
+
+	got, err := c.Get("py-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Unix(1700000000, 5e8); !got.DateTime.Equal(want) {
+		t.Fatalf("expected time %v, got %v", want, got.DateTime)
+	}
+
+	res, err := c.ProcessOnce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Fired != 2 {
+		t.Fatalf("expected 2 fired triggers, got %d", res.Fired)
+	}
+	py1 := fired["py-1"]
+	if py1 == nil || py1.Namespace != "billing" || string(py1.Payload) != `{"user":42}` || py1.Labels["lang"] != "python" {
+		t.Fatalf("unexpected fired trigger %v", py1)
+	}
+	py2 := fired["py-2"]
+	if py2 == nil || !py2.DateTime.Equal(time.Unix(1700000001, 0)) || !py2.Deadline.IsZero() || !py2.EarliestStart.IsZero() {
+		t.Fatalf("unexpected fired trigger %v", py2)
+	}
+	if _, err = c.Get("py-1"); err != ErrNotFound {
+		t.Fatalf("expected fired trigger to be removed, got %v", err)
+	}
+}